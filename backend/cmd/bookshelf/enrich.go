@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"talking-bookshelf/backend/internal/enrich"
+	"talking-bookshelf/backend/internal/store"
+)
+
+// runEnrich fills in book details from each book's ISBN (METADATA_PROVIDER selects the source).
+// Private notes are written back as they were read, so encrypted files stay encrypted.
+func runEnrich(args []string) int {
+	fs := flag.NewFlagSet("enrich", flag.ExitOnError)
+	force := fs.Bool("force", false, "overwrite fields that are already set")
+	dryRun := fs.Bool("dry-run", false, "print the changes without writing books.json")
+	id := fs.String("id", "", "only enrich this book")
+	fs.Parse(args)
+
+	path := defaultBooksPath
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	books, err := store.LoadBooksJSON(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	cachePath := filepath.Join(filepath.Dir(path), enrich.CacheFile)
+	provider, err := enrich.NewMetadataProvider(cachePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if provider == nil {
+		fmt.Fprintln(os.Stderr, "METADATA_PROVIDER=none: nothing to do")
+		return 1
+	}
+
+	ctx := context.Background()
+	enriched, failed, found := 0, 0, false
+	for i := range books {
+		book := &books[i]
+		if *id != "" && book.ID != *id {
+			continue
+		}
+		found = true
+
+		changes, err := enrich.Book(ctx, provider, book, *force)
+		switch {
+		case errors.Is(err, enrich.ErrNoISBN):
+			continue
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", book.ID, book.Title, err)
+			failed++
+			continue
+		case len(changes) == 0:
+			continue
+		}
+
+		fmt.Printf("%s %s\n", book.ID, book.Title)
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		enriched++
+	}
+	if *id != "" && !found {
+		fmt.Fprintf(os.Stderr, "book %s not found in %s\n", *id, path)
+		return 1
+	}
+
+	if enriched > 0 && !*dryRun {
+		if err := store.SaveBooksJSON(path, books); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+	fmt.Printf("%d enriched from %s, %d failed\n", enriched, provider.Name(), failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
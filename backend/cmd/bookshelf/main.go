@@ -0,0 +1,237 @@
+// Command bookshelf provides maintenance commands for the bookshelf data.
+//
+//	go run ./cmd/bookshelf lint [--fix] [path/to/books.json]
+//	go run ./cmd/bookshelf embed [path/to/books.json]
+//	go run ./cmd/bookshelf blurbs [--force] [--dry-run] [path/to/books.json]
+//	go run ./cmd/bookshelf enrich [--force] [--dry-run] [--id book-001] [path/to/books.json]
+//	go run ./cmd/bookshelf keygen
+//	go run ./cmd/bookshelf encrypt|decrypt [path/to/books.json]
+//	go run ./cmd/bookshelf rotate-key --new-key-file path [path/to/books.json]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"talking-bookshelf/backend/internal/agent"
+	"talking-bookshelf/backend/internal/blurb"
+	"talking-bookshelf/backend/internal/lint"
+	"talking-bookshelf/backend/internal/semantic"
+	"talking-bookshelf/backend/internal/store"
+
+	"google.golang.org/genai"
+)
+
+const defaultBooksPath = "data/books.json"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "lint":
+		os.Exit(runLint(args))
+	case "embed":
+		os.Exit(runEmbed(args))
+	case "blurbs":
+		os.Exit(runBlurbs(args))
+	case "enrich":
+		os.Exit(runEnrich(args))
+	case "keygen":
+		os.Exit(runKeygen(args))
+	case "encrypt":
+		os.Exit(runEncrypt(args))
+	case "decrypt":
+		os.Exit(runDecrypt(args))
+	case "rotate-key":
+		os.Exit(runRotateKey(args))
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: bookshelf <command> [flags]
+
+Commands:
+  lint [--fix] [books.json]   check books.json for broken links, ids, dates, languages and ISBNs
+  embed [books.json]          precompute the semantic search vectors (EMBEDDER selects the model)
+  blurbs [--force] [--dry-run] [books.json]
+                              generate public summaries for the feeds (needs GEMINI_API_KEY)
+  enrich [--force] [--dry-run] [--id book-001] [books.json]
+                              fill in title, authors, year, pages, subjects and cover from the ISBN
+                              (METADATA_PROVIDER=openlibrary|fixture); set fields are kept unless --force
+  keygen                      print a new private notes key (base64)
+  encrypt [books.json]        encrypt private notes with NOTES_KEY / NOTES_KEY_FILE
+  decrypt [books.json]        decrypt private notes back to plaintext
+  rotate-key --new-key-file path [books.json]
+                              re-encrypt the note keys from the current key to the new one`)
+}
+
+// runLint reports issues and returns 1 if errors remain
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "regenerate links and normalize dates, languages and ISBNs")
+	fs.Parse(args)
+
+	path := defaultBooksPath
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	issues, books, err := lint.CheckFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if *fix {
+		changes := lint.Fix(books)
+		if len(changes) > 0 {
+			if err := store.SaveBooksJSON(path, books); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				return 1
+			}
+			for _, change := range changes {
+				fmt.Printf("fixed %s\n", change)
+			}
+		}
+		// Re-check so positions and remaining issues reflect the rewritten file
+		if issues, _, err = lint.CheckFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%d books, %d issues\n", len(books), len(issues))
+
+	if lint.HasErrors(issues) {
+		return 1
+	}
+	return 0
+}
+
+// runEmbed refreshes the vector cache next to books.json so the server starts without embedding
+func runEmbed(args []string) int {
+	fs := flag.NewFlagSet("embed", flag.ExitOnError)
+	fs.Parse(args)
+
+	path := defaultBooksPath
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	books, encrypted, err := loadDecryptedBooks(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if encrypted {
+		fmt.Fprintln(os.Stderr, "notes are encrypted: the vector cache is not written (the server embeds them at startup)")
+		return 1
+	}
+
+	ctx := context.Background()
+	embedder, err := semantic.NewEmbedder(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if embedder == nil {
+		fmt.Fprintln(os.Stderr, "EMBEDDER=none: nothing to do")
+		return 1
+	}
+
+	cachePath := filepath.Join(filepath.Dir(path), semantic.CacheFile)
+	if err := semantic.NewIndex(embedder, cachePath).Update(ctx, books); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Printf("Vector cache %s is up to date (%s)\n", cachePath, embedder.Name())
+	return 0
+}
+
+// runBlurbs generates a public summary for each book without an excerpt whose
+// cached summary is missing or stale. Summaries that fail the checks are not cached.
+func runBlurbs(args []string) int {
+	fs := flag.NewFlagSet("blurbs", flag.ExitOnError)
+	force := fs.Bool("force", false, "regenerate summaries even if the cached one is current")
+	dryRun := fs.Bool("dry-run", false, "print the summaries without writing the cache")
+	fs.Parse(args)
+
+	path := defaultBooksPath
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	books, _, err := loadDecryptedBooks(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		fmt.Fprintln(os.Stderr, "GEMINI_API_KEY is not set")
+		return 1
+	}
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: apiKey})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create genai client: %v\n", err)
+		return 1
+	}
+	generator := blurb.NewGenerator(agent.NewGeminiLLMClient(client, agent.ValidationModel), agent.ValidationModel)
+
+	cachePath := filepath.Join(filepath.Dir(path), blurb.CacheFile)
+	cache, err := blurb.LoadCache(cachePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	generated, failed := 0, 0
+	for _, book := range books {
+		if strings.TrimSpace(book.Excerpt) != "" || strings.TrimSpace(book.PrivateNotes) == "" {
+			continue
+		}
+		if _, ok := cache.Get(book); ok && !*force {
+			continue
+		}
+
+		entry, err := generator.Generate(ctx, book)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", book.ID, book.Title, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s\n  %s\n", book.ID, book.Title, entry.Text)
+		cache.Books[book.ID] = entry
+		generated++
+	}
+
+	if generated > 0 && !*dryRun {
+		if err := cache.Save(cachePath); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+	fmt.Printf("%d generated, %d rejected\n", generated, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/notescrypt"
+	"talking-bookshelf/backend/internal/store"
+)
+
+// loadDecryptedBooks reads books.json with its private notes decrypted, like the server does,
+// and reports whether they were encrypted
+func loadDecryptedBooks(path string) ([]model.Book, bool, error) {
+	books, err := store.LoadBooksJSON(path)
+	if err != nil {
+		return nil, false, err
+	}
+	notes, err := store.NotesPolicyFromEnv()
+	if err != nil {
+		return nil, false, err
+	}
+	encrypted, err := notes.Open(books, path)
+	if err != nil {
+		return nil, false, err
+	}
+	return books, encrypted, nil
+}
+
+// runKeygen prints a new key for NOTES_KEY or NOTES_KEY_FILE
+func runKeygen(args []string) int {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	fs.Parse(args)
+
+	key, err := notescrypt.GenerateKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Println(key)
+	return 0
+}
+
+// runEncrypt seals every plaintext note with the configured key
+func runEncrypt(args []string) int {
+	path, key, books, ok := openNotesFile("encrypt", args)
+	if !ok {
+		return 1
+	}
+
+	n, err := store.EncryptNotes(books, key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return saveNotesFile(path, books, fmt.Sprintf("%d notes encrypted with key %s", n, key.ID()))
+}
+
+// runDecrypt writes every note back as plaintext
+func runDecrypt(args []string) int {
+	path, key, books, ok := openNotesFile("decrypt", args)
+	if !ok {
+		return 1
+	}
+
+	encrypted, _ := store.NotesStats(books)
+	if err := store.DecryptNotes(books, key); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return saveNotesFile(path, books, fmt.Sprintf("%d notes decrypted", encrypted))
+}
+
+// runRotateKey rewraps the note keys from the configured key to the new one.
+// Afterwards NOTES_KEY / NOTES_KEY_FILE must point to the new key.
+func runRotateKey(args []string) int {
+	fs := flag.NewFlagSet("rotate-key", flag.ExitOnError)
+	newKeyFile := fs.String("new-key-file", "", "file with the new base64 key (see `bookshelf keygen`)")
+	fs.Parse(args)
+
+	if *newKeyFile == "" {
+		fmt.Fprintln(os.Stderr, "--new-key-file is required")
+		return 2
+	}
+	next, err := notescrypt.LoadKeyFile(*newKeyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	path, key, books, ok := openNotesFile("rotate-key", fs.Args())
+	if !ok {
+		return 1
+	}
+	if next.ID() == key.ID() {
+		fmt.Fprintln(os.Stderr, "the new key is the same as the current key")
+		return 1
+	}
+
+	n, err := store.RewrapNotes(books, key, next)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return saveNotesFile(path, books, fmt.Sprintf("%d notes moved from key %s to %s; point NOTES_KEY / NOTES_KEY_FILE at the new key", n, key.ID(), next.ID()))
+}
+
+// openNotesFile parses the books.json argument and loads the file and the current key
+func openNotesFile(name string, args []string) (string, *notescrypt.Key, []model.Book, bool) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Parse(args)
+
+	path := defaultBooksPath
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	key, err := notescrypt.LoadKeyFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return "", nil, nil, false
+	}
+	if key == nil {
+		fmt.Fprintln(os.Stderr, "NOTES_KEY or NOTES_KEY_FILE is not set")
+		return "", nil, nil, false
+	}
+
+	books, err := store.LoadBooksJSON(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return "", nil, nil, false
+	}
+	return path, key, books, true
+}
+
+func saveNotesFile(path string, books []model.Book, summary string) int {
+	if err := store.SaveBooksJSON(path, books); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Println(summary)
+	return 0
+}
@@ -0,0 +1,96 @@
+// Command import-goodreads merges a Goodreads library export into books.json.
+//
+//	go run ./cmd/import-goodreads -csv goodreads_library_export.csv [-books data/books.json] [-dry-run]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"talking-bookshelf/backend/internal/importer"
+	"talking-bookshelf/backend/internal/notescrypt"
+	"talking-bookshelf/backend/internal/store"
+)
+
+func main() {
+	csvPath := flag.String("csv", "", "path to the Goodreads library export CSV")
+	booksPath := flag.String("books", "data/books.json", "books.json to merge into")
+	dryRun := flag.Bool("dry-run", false, "print the changes without writing books.json")
+	flag.Parse()
+
+	if *csvPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*csvPath)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to open CSV: %v", err)
+	}
+	defer f.Close()
+
+	parsed, err := importer.ParseGoodreadsCSV(f)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	existing, err := store.LoadBooksJSON(*booksPath)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	result := importer.Merge(existing, parsed.Books)
+	printDiff(result, parsed.Skipped)
+
+	if *dryRun {
+		fmt.Println("Dry run: books.json was not modified")
+		return
+	}
+	if len(result.Changes) == 0 {
+		return
+	}
+
+	if err := store.ValidateBooks(result.Books); err != nil {
+		log.Fatalf("[FATAL] Merged data is invalid: %v", err)
+	}
+	// Imported reviews must not leave plaintext notes in an encrypted file
+	if encrypted, _ := store.NotesStats(existing); encrypted > 0 {
+		key, err := notescrypt.LoadKeyFromEnv()
+		if err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+		if key == nil {
+			log.Fatalf("[FATAL] %s has encrypted notes: set NOTES_KEY or NOTES_KEY_FILE to import", *booksPath)
+		}
+		if _, err := store.EncryptNotes(result.Books, key); err != nil {
+			log.Fatalf("[FATAL] %v", err)
+		}
+	}
+	if err := store.SaveBooksJSON(*booksPath, result.Books); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	fmt.Printf("Wrote %d books to %s\n", len(result.Books), *booksPath)
+}
+
+func printDiff(result *importer.MergeResult, skipped []string) {
+	added, updated := 0, 0
+	for _, change := range result.Changes {
+		if change.Added {
+			added++
+			fmt.Printf("+ %s %s / %s (%s, %s)\n",
+				change.Book.ID, change.Book.Title, change.Book.Author, change.Book.Language, change.Book.FinishedAt)
+			continue
+		}
+		updated++
+		fmt.Printf("~ %s %s\n", change.Book.ID, change.Book.Title)
+		for _, field := range change.Fields {
+			fmt.Printf("    %s\n", field)
+		}
+	}
+	for _, reason := range skipped {
+		fmt.Printf("- skipped %s\n", reason)
+	}
+	fmt.Printf("%d added, %d updated, %d unchanged, %d skipped\n", added, updated, result.Unchanged, len(skipped))
+}
@@ -0,0 +1,73 @@
+// Command import-kindle attaches Kindle highlights from "My Clippings.txt" to the books in books.json.
+//
+//	go run ./cmd/import-kindle -clippings "My Clippings.txt" [-books data/books.json] [-dry-run]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"talking-bookshelf/backend/internal/importer"
+	"talking-bookshelf/backend/internal/store"
+)
+
+func main() {
+	clippingsPath := flag.String("clippings", "", `path to the Kindle "My Clippings.txt"`)
+	booksPath := flag.String("books", "data/books.json", "books.json to add highlights to")
+	dryRun := flag.Bool("dry-run", false, "print the changes without writing books.json")
+	flag.Parse()
+
+	if *clippingsPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*clippingsPath)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to open clippings: %v", err)
+	}
+	defer f.Close()
+
+	clippings, skipped, err := importer.ParseKindleClippings(f)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	books, err := store.LoadBooksJSON(*booksPath)
+	if err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+
+	matches := importer.MergeHighlights(books, importer.GroupClippings(clippings))
+
+	added := 0
+	for _, m := range matches {
+		if m.Book == nil {
+			fmt.Printf("? %s (%s): no matching book, %d highlights not imported\n",
+				m.Group.Title, m.Group.Author, len(m.Group.Highlights))
+			continue
+		}
+		fmt.Printf("+ %s %s: %d new highlights (%d in file, %d bookmarks ignored)\n",
+			m.Book.ID, m.Book.Title, m.Added, len(m.Group.Highlights), m.Group.Bookmarks)
+		added += m.Added
+	}
+	for _, reason := range skipped {
+		fmt.Printf("- skipped %s\n", reason)
+	}
+	fmt.Printf("%d clippings read, %d highlights added, %d skipped\n", len(clippings), added, len(skipped))
+
+	if *dryRun {
+		fmt.Println("Dry run: books.json was not modified")
+		return
+	}
+	if added == 0 {
+		return
+	}
+
+	if err := store.SaveBooksJSON(*booksPath, books); err != nil {
+		log.Fatalf("[FATAL] %v", err)
+	}
+	fmt.Printf("Wrote %s\n", *booksPath)
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"talking-bookshelf/backend/internal/handler"
 	"talking-bookshelf/backend/internal/middleware"
+	"talking-bookshelf/backend/internal/shelf"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -21,6 +24,11 @@ func main() {
 	env := os.Getenv("ENV")
 	log.Printf("[INFO] Starting Talking Bookshelf env=%s", env)
 
+	// Open the book store once; handlers, agent tools and validators share it
+	if err := handler.InitBookRepository(); err != nil {
+		log.Fatalf("[FATAL] Failed to open book store: %v", err)
+	}
+
 	if err := handler.InitBookshelfAgent(); err != nil {
 		log.Printf("[WARN] Failed to initialize Bookshelf agent: %v", err)
 		log.Println("[WARN] Chat functionality will be unavailable")
@@ -28,6 +36,9 @@ func main() {
 		log.Println("[INFO] Bookshelf agent initialized successfully")
 	}
 
+	// Additional shelves under data/shelves/<slug>/, each with its own agent
+	handler.InitShelves()
+
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -50,16 +61,15 @@ func main() {
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Accept-Language"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Content-Type", "Accept-Language", "Authorization"},
+		ExposeHeaders:    []string{"X-Total-Count", "Link"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}))
 
 	// Initialize rate limiters
-	// 具体的な値は公開リポジトリから省略
-	ipLimiter := middleware.NewIPRateLimiter(rate.Every(1*time.Second), 1)
-	dailyQuota := middleware.NewDailyQuota(1)
+	ipLimiter, dailyQuota := newChatLimits()
 
 	log.Printf("[INFO] Rate limiting enabled")
 
@@ -69,21 +79,124 @@ func main() {
 
 	api := r.Group("/api")
 	{
-		api.GET("/books", handler.HandleGetBooks)
-		api.GET("/books/:id", handler.HandleGetBook)
-		api.GET("/owner", handler.HandleGetOwner)
+		// Catalog responses only change on reload: ETag / Last-Modified + per-route Cache-Control
+		api.GET("/books", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetBooks)
+		api.GET("/books/:id", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOK", defaultCatalogCacheControl)), handler.HandleGetBook)
+		api.GET("/books/:id/related", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOK", defaultCatalogCacheControl)), handler.HandleGetRelatedBooks)
+		api.GET("/owner", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_OWNER", defaultCatalogCacheControl)), handler.HandleGetOwner)
+		api.GET("/tags", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetTags)
+		api.GET("/genres", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetGenres)
+		// Named shelves (collections) of books; the per-owner shelves below are listed at /api/tenants
+		api.GET("/shelves", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetCollections)
+		api.GET("/authors", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetAuthors)
+		api.GET("/authors/:slug", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetAuthor)
+		api.GET("/stats", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_STATS", defaultCatalogCacheControl)), handler.HandleGetStats)
 		api.POST("/chat", middleware.RateLimitMiddleware(ipLimiter, dailyQuota), handler.HandleChat)
+
+		// Per-shelf routes; "default" is an alias of the routes above and shares their quota
+		api.GET("/tenants", handler.HandleListShelves)
+		shelves := api.Group("/shelves/:slug", handler.RequireShelf)
+		{
+			shelves.GET("/books", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetBooks)
+			shelves.GET("/books/:id", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOK", defaultCatalogCacheControl)), handler.HandleGetBook)
+			shelves.GET("/books/:id/related", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOK", defaultCatalogCacheControl)), handler.HandleGetRelatedBooks)
+			shelves.GET("/owner", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_OWNER", defaultCatalogCacheControl)), handler.HandleGetOwner)
+			shelves.GET("/tags", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetTags)
+			shelves.GET("/genres", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetGenres)
+			shelves.GET("/shelves", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetCollections)
+			shelves.GET("/authors", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetAuthors)
+			shelves.GET("/authors/:slug", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_BOOKS", defaultCatalogCacheControl)), handler.HandleGetAuthor)
+			shelves.GET("/stats", middleware.ConditionalGETFor(handler.ShelfDataVersion, cachePolicy("CACHE_CONTROL_STATS", defaultCatalogCacheControl)), handler.HandleGetStats)
+			shelves.GET("/covers/:id", middleware.CacheControl(cachePolicy("CACHE_CONTROL_COVERS", defaultCoverCacheControl)), handler.HandleGetCover)
+			shelves.POST("/chat", middleware.ShelfRateLimitMiddleware(func(slug string) (*middleware.IPRateLimiter, *middleware.DailyQuota) {
+				if slug == shelf.DefaultSlug {
+					return ipLimiter, dailyQuota
+				}
+				return newChatLimits()
+			}), handler.HandleChat)
+		}
+	}
+
+	// OPDS catalog for reading apps (Atom feeds, cached like the JSON catalog)
+	catalog := r.Group("/opds", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_OPDS", defaultCatalogCacheControl)))
+	{
+		catalog.GET("", handler.HandleOPDSRoot)
+		catalog.GET("/opensearch.xml", handler.HandleOPDSSearchDescription)
+		catalog.GET("/search", handler.HandleOPDSSearch)
+		catalog.GET("/books", handler.HandleOPDSBooks)
+		catalog.GET("/authors", handler.HandleOPDSAuthors)
+		catalog.GET("/authors/:name", handler.HandleOPDSAuthor)
+		catalog.GET("/years", handler.HandleOPDSYears)
+		catalog.GET("/years/:year", handler.HandleOPDSYear)
+		catalog.GET("/languages", handler.HandleOPDSLanguages)
+		catalog.GET("/languages/:lang", handler.HandleOPDSLanguage)
+	}
+
+	// Cover images, thumbnails (?w=) and placeholders; validated by Last-Modified / ETag per image
+	r.GET("/covers/:id", middleware.CacheControl(cachePolicy("CACHE_CONTROL_COVERS", defaultCoverCacheControl)), handler.HandleGetCover)
+
+	// Feeds of recently finished books
+	feeds := r.Group("", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_FEED", defaultCatalogCacheControl)))
+	{
+		feeds.GET("/feed.atom", handler.HandleAtomFeed)
+		feeds.GET("/feed.rss", handler.HandleRSSFeed)
+	}
+
+	// Owner-only endpoints are registered only when ADMIN_TOKEN is configured
+	if adminToken := os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		admin := api.Group("/admin", middleware.AdminAuth(adminToken))
+		{
+			admin.POST("/reload", handler.HandleReload)
+			admin.POST("/shelves/:slug/reload", handler.HandleReloadShelf)
+			admin.POST("/books", handler.HandleAdminCreateBook)
+			admin.PUT("/books/:id", handler.HandleAdminReplaceBook)
+			admin.PATCH("/books/:id", handler.HandleAdminUpdateBook)
+			admin.DELETE("/books/:id", handler.HandleAdminDeleteBook)
+			admin.POST("/books/:id/enrich", handler.HandleAdminEnrichBook)
+		}
+		log.Printf("[INFO] Admin endpoints enabled")
+	}
+
+	// Hot reload of books.json / portfolio.json (DATA_WATCH_INTERVAL=0 disables)
+	watchInterval := handler.DefaultDataWatchInterval
+	if v := os.Getenv("DATA_WATCH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("[FATAL] Invalid DATA_WATCH_INTERVAL %q: %v", v, err)
+		}
+		watchInterval = d
 	}
+	if watchInterval > 0 {
+		go handler.WatchDataFiles(context.Background(), watchInterval)
+	}
+
+	// Crawlers: sitemap of the server-rendered pages
+	r.GET("/sitemap.xml", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_PAGES", defaultCatalogCacheControl)), handler.HandleSitemap)
+	r.GET("/robots.txt", handler.HandleRobots)
 
 	if env == "production" {
-		r.Static("/assets", "/app/static/assets")
+		// Built frontend (dist); STATIC_DIR overrides the container path
+		staticDir := "/app/static"
+		if v := os.Getenv("STATIC_DIR"); v != "" {
+			staticDir = v
+		}
+		r.Static("/assets", filepath.Join(staticDir, "assets"))
+
+		// Home and book pages are rendered on the server into index.html; the React app boots on top
+		if err := handler.InitPages(filepath.Join(staticDir, "index.html")); err != nil {
+			log.Printf("[WARN] Server-rendered pages disabled: %v", err)
+		} else {
+			pages := r.Group("", middleware.ConditionalGET(handler.DataVersion, cachePolicy("CACHE_CONTROL_PAGES", defaultCatalogCacheControl)))
+			pages.GET("/", handler.HandleHomePage)
+			pages.GET("/books/:id", handler.HandleBookPage)
+		}
 
 		r.NoRoute(func(c *gin.Context) {
 			if strings.HasPrefix(c.Request.URL.Path, "/api") {
 				c.JSON(404, gin.H{"error": "Not found"})
 				return
 			}
-			c.File("/app/static/index.html")
+			c.File(filepath.Join(staticDir, "index.html"))
 		})
 	}
 
@@ -97,3 +210,24 @@ func main() {
 		log.Fatalf("[FATAL] Failed to start server: %v", err)
 	}
 }
+
+// newChatLimits creates the per-IP limiter and daily quota of one shelf's chat
+// 具体的な値は公開リポジトリから省略
+func newChatLimits() (*middleware.IPRateLimiter, *middleware.DailyQuota) {
+	return middleware.NewIPRateLimiter(rate.Every(1*time.Second), 1), middleware.NewDailyQuota(1)
+}
+
+// defaultCatalogCacheControl lets clients keep catalog responses but revalidate them with the ETag
+const defaultCatalogCacheControl = "public, no-cache"
+
+// defaultCoverCacheControl lets clients reuse local cover files for a day before revalidating
+// (placeholders and redirects to external covers are always sent with no-cache)
+const defaultCoverCacheControl = "public, max-age=86400"
+
+// cachePolicy returns the Cache-Control value for a route, overridable by env (empty value = no header)
+func cachePolicy(envName, fallback string) string {
+	if v, ok := os.LookupEnv(envName); ok {
+		return v
+	}
+	return fallback
+}
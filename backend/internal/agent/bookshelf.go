@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	adkmodel "google.golang.org/adk/model"
 	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
@@ -32,26 +33,56 @@ const (
 	RecentTurnsToKeep = 3
 	// RecentConversationStateKey is the key for storing recent conversation in session state
 	RecentConversationStateKey = "recent_conversation"
+	// DefaultAppName is the ADK app name of the default shelf
+	DefaultAppName = "talking_bookshelf"
 )
 
+// Options configures an agent for one shelf
+type Options struct {
+	// AppName is the ADK app name; sessions are namespaced by it (DefaultAppName if empty)
+	AppName string
+	// Persona is added to the system prompt to give the shelf its owner's voice (optional)
+	Persona string
+}
+
 // ChatResponse is the parsed response from the agent (re-exported for handler compatibility)
 type ChatResponse = response.ChatResponse
 
+// Quote is a resolved [quote::book-id::n] annotation (re-exported for the handler)
+type Quote = response.Quote
+
 // BookshelfAgent wraps the ADK agent and runner
 type BookshelfAgent struct {
-	runner           *runner.Runner
-	sessionService   session.Service
-	genaiClient      *genai.Client
-	bookRepo         deps.BookRepository
-	portfolio        *portfolio.Portfolio
-	promptBuilder    *prompt.Builder
-	pipeline         *validation.Pipeline
+	runner         *runner.Runner
+	sessionService session.Service
+	genaiClient    *genai.Client
+	geminiModel    adkmodel.LLM
+	bookRepo       deps.BookRepository
+	noteSearcher   deps.NoteSearcher
+	portfolio      *portfolio.Portfolio
+	promptBuilder  *prompt.Builder
+	pipeline       *validation.Pipeline
+	state          *conversationState
+	appName        string
+	persona        string
+}
+
+// conversationState holds per-session bookkeeping that must survive data reloads
+type conversationState struct {
 	mu               sync.Mutex
 	recommendedBooks map[string][]string // sessionID -> recommended book IDs
 }
 
-// NewBookshelfAgent creates a new ADK-based bookshelf agent
-func NewBookshelfAgent(ctx context.Context, books []model.Book, p *portfolio.Portfolio) (*BookshelfAgent, error) {
+// recommended returns the books already recommended in a conversation
+func (s *conversationState) recommended(sessionID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recommendedBooks[sessionID]
+}
+
+// NewBookshelfAgent creates a new ADK-based bookshelf agent.
+// noteSearcher may be nil, in which case semantic_search_books is not offered.
+func NewBookshelfAgent(ctx context.Context, bookRepo deps.BookRepository, noteSearcher deps.NoteSearcher, p *portfolio.Portfolio, opts Options) (*BookshelfAgent, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("GEMINI_API_KEY is not set")
@@ -73,8 +104,29 @@ func NewBookshelfAgent(ctx context.Context, books []model.Book, p *portfolio.Por
 		return nil, fmt.Errorf("failed to create Gemini model: %w", err)
 	}
 
+	base := &BookshelfAgent{
+		sessionService: session.InMemoryService(),
+		genaiClient:    genaiClient,
+		geminiModel:    geminiModel,
+		noteSearcher:   noteSearcher,
+		state:          &conversationState{recommendedBooks: make(map[string][]string)},
+		appName:        opts.AppName,
+		persona:        opts.Persona,
+	}
+	if base.appName == "" {
+		base.appName = DefaultAppName
+	}
+	return base.WithData(bookRepo, p)
+}
+
+// WithData returns a new agent serving the given books and portfolio.
+// The API clients, ADK sessions and recommendation history are shared with a,
+// so reloading data keeps conversations alive while chats already running on a
+// finish against the old data.
+func (a *BookshelfAgent) WithData(bookRepo deps.BookRepository, p *portfolio.Portfolio) (*BookshelfAgent, error) {
 	// Build tools (with portfolio for get_owner_info)
-	toolBuilder := NewBookshelfTools(books, p)
+	toolBuilder := NewBookshelfTools(bookRepo, a.noteSearcher, p)
+	toolBuilder.conversation = a.state
 	tools, err := toolBuilder.BuildTools()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build tools: %w", err)
@@ -82,12 +134,12 @@ func NewBookshelfAgent(ctx context.Context, books []model.Book, p *portfolio.Por
 
 	// Create prompt builder and system prompt (portfolio removed - now a tool)
 	promptBuilder := prompt.NewBuilder()
-	systemPrompt := promptBuilder.BuildSystemPrompt()
+	systemPrompt := promptBuilder.BuildSystemPromptWithPersona(a.persona)
 
 	// Create LLM agent
 	llmAgent, err := llmagent.New(llmagent.Config{
 		Name:        "talking_bookshelf",
-		Model:       geminiModel,
+		Model:       a.geminiModel,
 		Description: "A talking bookshelf that represents the owner's reading experience and portfolio.",
 		Instruction: systemPrompt,
 		Tools:       tools,
@@ -101,42 +153,43 @@ func NewBookshelfAgent(ctx context.Context, books []model.Book, p *portfolio.Por
 		return nil, fmt.Errorf("failed to create LLM agent: %w", err)
 	}
 
-	// Create session service
-	sessionService := session.InMemoryService()
-
-	// Create runner
+	// Create runner (session service is shared across reloads)
 	r, err := runner.New(runner.Config{
-		AppName:        "talking_bookshelf",
+		AppName:        a.appName,
 		Agent:          llmAgent,
-		SessionService: sessionService,
+		SessionService: a.sessionService,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create runner: %w", err)
 	}
 
-	// Create book repository and LLM client
-	bookRepo := NewInMemoryBookRepository(books)
-	llmClient := NewGeminiLLMClient(genaiClient, ValidationModel)
+	// Create LLM client
+	llmClient := NewGeminiLLMClient(a.genaiClient, ValidationModel)
 
 	// Create validation pipeline
 	corrector := validation.NewResponseCorrector(llmClient, bookRepo, promptBuilder)
 	pipeline := validation.NewPipeline(
 		[]validation.Validator{
-			validation.NewPromptLeakValidator(),             // First: check for prompt leaks
-			validation.NewBookAnnotationValidator(bookRepo), // Second: validate book annotations
+			validation.NewPromptLeakValidator(),              // First: check for prompt leaks
+			validation.NewBookAnnotationValidator(bookRepo),  // Second: validate book annotations
+			validation.NewQuoteAnnotationValidator(bookRepo), // Third: check quotes against the stored highlights
 		},
 		corrector,
 	)
 
 	return &BookshelfAgent{
-		runner:           r,
-		sessionService:   sessionService,
-		genaiClient:      genaiClient,
-		bookRepo:         bookRepo,
-		portfolio:        p,
-		promptBuilder:    promptBuilder,
-		pipeline:         pipeline,
-		recommendedBooks: make(map[string][]string),
+		runner:         r,
+		sessionService: a.sessionService,
+		genaiClient:    a.genaiClient,
+		geminiModel:    a.geminiModel,
+		bookRepo:       bookRepo,
+		noteSearcher:   a.noteSearcher,
+		portfolio:      p,
+		promptBuilder:  promptBuilder,
+		pipeline:       pipeline,
+		state:          a.state,
+		appName:        a.appName,
+		persona:        a.persona,
 	}, nil
 }
 
@@ -156,9 +209,9 @@ func (a *BookshelfAgent) Chat(ctx context.Context, userID, sessionID, message st
 	}
 
 	// Get previously recommended books from internal map (BEFORE running agent)
-	a.mu.Lock()
-	previousBooks := a.recommendedBooks[newSessionID]
-	a.mu.Unlock()
+	a.state.mu.Lock()
+	previousBooks := a.state.recommendedBooks[newSessionID]
+	a.state.mu.Unlock()
 	if len(previousBooks) > 0 {
 		log.Printf("[BOOKS] Previously recommended books: %v", previousBooks)
 	}
@@ -238,9 +291,9 @@ func (a *BookshelfAgent) Chat(ctx context.Context, userID, sessionID, message st
 		// Deduplicate
 		allBooks = deduplicateStrings(allBooks)
 		// Save to internal map (session state doesn't persist with ADK InMemoryService)
-		a.mu.Lock()
-		a.recommendedBooks[newSessionID] = allBooks
-		a.mu.Unlock()
+		a.state.mu.Lock()
+		a.state.recommendedBooks[newSessionID] = allBooks
+		a.state.mu.Unlock()
 		log.Printf("[BOOKS] Saved recommended books to internal map: %v", allBooks)
 	}
 
@@ -251,13 +304,38 @@ func (a *BookshelfAgent) Chat(ctx context.Context, userID, sessionID, message st
 		Response:    cleanedResponse.Response,
 		Emotion:     parsed.Emotion,
 		Suggestions: parsed.Suggestions,
+		Quotes:      a.resolveQuotes(cleanedResponse.Response),
 	}, nil
 }
 
+// resolveQuotes looks up the passages of the quote annotations in the response.
+// Annotations that don't resolve (e.g. after a failed validation) are left out.
+func (a *BookshelfAgent) resolveQuotes(text string) map[string]response.Quote {
+	var quotes map[string]response.Quote
+	for _, ann := range validation.ExtractQuoteAnnotations(text) {
+		highlight, err := validation.ResolveQuote(a.bookRepo, ann.BookID, ann.Index)
+		if err != nil {
+			continue
+		}
+		if quotes == nil {
+			quotes = make(map[string]response.Quote)
+		}
+		book := a.bookRepo.GetByID(ann.BookID)
+		quotes[model.QuoteRef(ann.BookID, ann.Index)] = response.Quote{
+			BookID:   ann.BookID,
+			Title:    book.Title,
+			Text:     highlight.Text,
+			Location: highlight.Location,
+			Page:     highlight.Page,
+		}
+	}
+	return quotes
+}
+
 // CreateSession creates a new session for a user
 func (a *BookshelfAgent) CreateSession(ctx context.Context, userID string) (string, error) {
 	resp, err := a.sessionService.Create(ctx, &session.CreateRequest{
-		AppName: "talking_bookshelf",
+		AppName: a.appName,
 		UserID:  userID,
 	})
 	if err != nil {
@@ -269,7 +347,7 @@ func (a *BookshelfAgent) CreateSession(ctx context.Context, userID string) (stri
 // getRecentConversation retrieves recent conversation from session state
 func (a *BookshelfAgent) getRecentConversation(ctx context.Context, userID, sessionID string) (string, error) {
 	getResp, err := a.sessionService.Get(ctx, &session.GetRequest{
-		AppName:   "talking_bookshelf",
+		AppName:   a.appName,
 		UserID:    userID,
 		SessionID: sessionID,
 	})
@@ -291,11 +369,11 @@ func (a *BookshelfAgent) getRecentConversation(ctx context.Context, userID, sess
 // compactSessionHistory checks if history needs compaction and creates a new session
 // Preserves recent conversation (last 5 turns) in session state
 func (a *BookshelfAgent) compactSessionHistory(ctx context.Context, userID, sessionID string) (string, error) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
 
 	getResp, err := a.sessionService.Get(ctx, &session.GetRequest{
-		AppName:   "talking_bookshelf",
+		AppName:   a.appName,
 		UserID:    userID,
 		SessionID: sessionID,
 	})
@@ -323,7 +401,7 @@ func (a *BookshelfAgent) compactSessionHistory(ctx context.Context, userID, sess
 
 	// Delete old session
 	if err := a.sessionService.Delete(ctx, &session.DeleteRequest{
-		AppName:   "talking_bookshelf",
+		AppName:   a.appName,
 		UserID:    userID,
 		SessionID: sessionID,
 	}); err != nil {
@@ -337,7 +415,7 @@ func (a *BookshelfAgent) compactSessionHistory(ctx context.Context, userID, sess
 	}
 
 	createResp, err := a.sessionService.Create(ctx, &session.CreateRequest{
-		AppName:   "talking_bookshelf",
+		AppName:   a.appName,
 		UserID:    userID,
 		SessionID: sessionID,
 		State:     initialState,
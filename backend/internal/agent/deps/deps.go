@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/related"
+	"talking-bookshelf/backend/internal/search"
 )
 
 // LLMClient abstracts LLM API calls for validation/summarization
@@ -15,5 +17,32 @@ type LLMClient interface {
 type BookRepository interface {
 	GetByID(id string) *model.Book
 	GetAll() []model.Book
-	Search(query string) []model.Book
+	Search(query string) []search.Result    // ranked, best first; falls back to fuzzy matching
+	SearchAll(query string) []search.Result // like Search but every match, for filtering and paging
+	Related(id string) []related.Match      // similar books, best first; nil for unknown IDs
+}
+
+// BookWriter persists book edits made through the admin API
+type BookWriter interface {
+	Save(book model.Book) error
+	Delete(id string) error
+}
+
+// Embedder turns text into vectors for semantic search.
+// Name identifies the model so cached vectors from another embedder are not reused.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NoteSearcher finds passages of the owner's notes by meaning rather than keywords
+type NoteSearcher interface {
+	SearchNotes(ctx context.Context, query string, limit int) ([]NoteMatch, error)
+}
+
+// NoteMatch is a notes passage similar to the query
+type NoteMatch struct {
+	BookID string
+	Text   string
+	Score  float64 // cosine similarity
 }
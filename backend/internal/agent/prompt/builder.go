@@ -21,6 +21,15 @@ func (b *Builder) BuildSystemPrompt() string {
 	return SystemPromptFlash
 }
 
+// BuildSystemPromptWithPersona appends the shelf owner's persona to the system prompt
+func (b *Builder) BuildSystemPromptWithPersona(persona string) string {
+	persona = strings.TrimSpace(persona)
+	if persona == "" {
+		return b.BuildSystemPrompt()
+	}
+	return b.BuildSystemPrompt() + "\n\n## Persona\n" + persona
+}
+
 // BuildValidationPrompt creates a prompt to validate a response against notes
 func (b *Builder) BuildValidationPrompt(notesContext, response, bookFormats string) string {
 	return fmt.Sprintf(ValidationPromptJa, notesContext, response, bookFormats)
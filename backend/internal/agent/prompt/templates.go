@@ -15,6 +15,7 @@ package prompt
 // - キャラクター設定（本棚としての人格・口調）
 // - 応答ルール（文数制限、言語対応、メモ準拠）
 // - 書籍リンク形式 [book::タイトル::id] の指定
+// - 引用形式 [quote::book-id::n] の指定（get_book_quotes の text を「」で引用し、直後に quote を添える）
 // - Function Calling ツールの使用指示
 // - 出力フォーマット（EMOTION, SUGGESTIONS タグ）
 // - 応答例
@@ -10,6 +10,17 @@ type ChatResponse struct {
 	Response    string   `json:"response"`
 	Emotion     string   `json:"emotion"`
 	Suggestions []string `json:"suggestions"`
+	// Quotes maps each [quote::book-id::n] annotation in Response to its stored passage
+	Quotes map[string]Quote `json:"quotes,omitempty"`
+}
+
+// Quote is the highlight behind a [quote::book-id::n] annotation, for rendering
+type Quote struct {
+	BookID   string `json:"bookId"`
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+	Location string `json:"location,omitempty"`
+	Page     string `json:"page,omitempty"`
 }
 
 const defaultEmotion = "talking"
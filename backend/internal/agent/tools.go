@@ -2,11 +2,19 @@ package agent
 
 import (
 	"log"
+	"math"
+	"regexp"
+	"slices"
 	"strings"
 
+	"talking-bookshelf/backend/internal/agent/deps"
 	"talking-bookshelf/backend/internal/agent/sanitize"
+	"talking-bookshelf/backend/internal/authors"
 	"talking-bookshelf/backend/internal/model"
 	"talking-bookshelf/backend/internal/portfolio"
+	"talking-bookshelf/backend/internal/related"
+	"talking-bookshelf/backend/internal/search"
+	"talking-bookshelf/backend/internal/stats"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -18,15 +26,21 @@ import (
 
 // search_books tool
 type searchBooksInput struct {
-	Query string `json:"query" jsonschema:"検索キーワード（タイトル、著者、メモから検索）"`
+	Query string `json:"query" jsonschema:"検索キーワード（タイトル、著者、メモから検索）。タグだけで絞り込むときは空でよい"`
+	Tag   string `json:"tag,omitempty" jsonschema:"このタグが付いた本だけに絞り込む（任意）"`
 }
 
 type bookSummary struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	Author       string `json:"author"`
-	Link         string `json:"link"` // [book:タイトル:book-id] format for AI to use directly
-	NotesExcerpt string `json:"notes_excerpt"`
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Author       string   `json:"author"`
+	Link         string   `json:"link"` // [book:タイトル:book-id] format for AI to use directly
+	NotesExcerpt string   `json:"notes_excerpt"`
+	Status       string   `json:"status"` // to-read / reading / finished / abandoned
+	Tags         []string `json:"tags,omitempty"`
+	Genres       []string `json:"genres,omitempty"`
+	Score        float64  `json:"score"`
+	Fuzzy        bool     `json:"fuzzy,omitempty"` // matched approximately (typo or kana/romaji spelling)
 }
 
 type searchBooksOutput struct {
@@ -36,29 +50,153 @@ type searchBooksOutput struct {
 
 // get_book_details tool
 type getBookDetailsInput struct {
-	BookID string `json:"book_id" jsonschema:"本のID（例: book-001）"`
+	BookID string `json:"book_id" jsonschema:"本のID（例: book-001）。IDが分からない場合はタイトル"`
 }
 
 type getBookDetailsOutput struct {
-	ID         string `json:"id"`
-	Title      string `json:"title"`
-	Author     string `json:"author"`
-	Link       string `json:"link"` // [book:タイトル:book-id] format for AI to use directly
-	FinishedAt string `json:"finished_at"`
-	Notes      string `json:"notes"`
-	Error      string `json:"error,omitempty"`
+	ID         string          `json:"id"`
+	Title      string          `json:"title"`
+	Author     string          `json:"author"`
+	Link       string          `json:"link"` // [book:タイトル:book-id] format for AI to use directly
+	FinishedAt string          `json:"finished_at"`
+	Status     string          `json:"status"`
+	StartedAt  string          `json:"started_at,omitempty"`
+	Progress   int             `json:"progress_percent,omitempty"`
+	Rating     int             `json:"rating,omitempty"`
+	Notes      string          `json:"notes"`
+	Highlights []highlightInfo `json:"highlights,omitempty"`
+	Error      string          `json:"error,omitempty"`
 }
 
-// get_reading_stats tool (no input needed)
-type getReadingStatsOutput struct {
-	TotalBooks   int            `json:"total_books"`
-	BooksPerYear map[string]int `json:"books_per_year"`
-	TopAuthors   []authorCount  `json:"top_authors"`
+type highlightInfo struct {
+	Quote    string   `json:"quote,omitempty"` // [quote::book-id::n] annotation to cite this passage (none for note-only entries)
+	Text     string   `json:"text,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Page     string   `json:"page,omitempty"`
+	Note     string   `json:"note,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// get_book_quotes tool
+type getBookQuotesInput struct {
+	BookID  string `json:"book_id,omitempty" jsonschema:"本のID（例: book-001）またはタイトル。省略時は全ての本から探す"`
+	Keyword string `json:"keyword,omitempty" jsonschema:"引用・コメント・タグに含まれるキーワード。省略時は全件"`
+}
+
+type bookQuote struct {
+	BookID string `json:"book_id"`
+	Title  string `json:"title"`
+	Link   string `json:"link"`
+	highlightInfo
+}
+
+type getBookQuotesOutput struct {
+	Quotes []bookQuote `json:"quotes"`
+	Count  int         `json:"count"`
+	Error  string      `json:"error,omitempty"`
 }
 
-type authorCount struct {
-	Author string `json:"author"`
-	Count  int    `json:"count"`
+// quotesLimit is the maximum number of highlights get_book_quotes returns
+const quotesLimit = 10
+
+// semantic_search_books tool
+type semanticSearchInput struct {
+	Query string `json:"query" jsonschema:"探したい内容（例: チームワークの考え方が変わった本）"`
+}
+
+type semanticMatch struct {
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Author  string  `json:"author"`
+	Link    string  `json:"link"`
+	Passage string  `json:"passage"` // the notes passage closest to the query
+	Score   float64 `json:"score"`
+}
+
+type semanticSearchOutput struct {
+	Books []semanticMatch `json:"books"`
+	Count int             `json:"count"`
+}
+
+// semanticSearchLimit is the number of books semantic_search_books returns
+const semanticSearchLimit = 5
+
+// get_books_by_author tool
+type getBooksByAuthorInput struct {
+	Author string `json:"author" jsonschema:"著者名。表記ゆれ・姓だけ・姓と名の間の空白の有無は問わない"`
+}
+
+type authorBook struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Link         string `json:"link"`
+	Status       string `json:"status"`
+	FinishedAt   string `json:"finished_at,omitempty"`
+	NotesExcerpt string `json:"notes_excerpt,omitempty"`
+}
+
+type getBooksByAuthorOutput struct {
+	Author     string       `json:"author,omitempty"` // the name as written on the shelf
+	Books      []authorBook `json:"books"`
+	Count      int          `json:"count"`
+	Candidates []string     `json:"candidates,omitempty"` // several authors match: ask which one was meant
+	Error      string       `json:"error,omitempty"`
+}
+
+// find_similar_books tool
+type findSimilarBooksInput struct {
+	BookID   string `json:"book_id" jsonschema:"似た本を探したい本のID（例: book-003）。IDが分からない場合はタイトル"`
+	Language string `json:"language,omitempty" jsonschema:"ja / en。ユーザーがその言語の本を求めたときだけ指定する（省略時は言語で絞らない）"`
+}
+
+type similarBook struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Author  string   `json:"author"`
+	Link    string   `json:"link"`
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons"` // why it is similar, e.g. "both discuss replication"
+	Score   float64  `json:"score"`
+}
+
+type findSimilarBooksOutput struct {
+	Books    []similarBook `json:"books"`
+	Count    int           `json:"count"`
+	Excluded []string      `json:"excluded,omitempty"` // similar books left out because they were already recommended
+	Error    string        `json:"error,omitempty"`
+}
+
+// get_current_reads / get_reading_list tools (no input needed)
+type statusBook struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Author       string `json:"author"`
+	Link         string `json:"link"`
+	StartedAt    string `json:"started_at,omitempty"`
+	Progress     int    `json:"progress_percent,omitempty"`
+	Page         int    `json:"page,omitempty"`
+	NotesExcerpt string `json:"notes_excerpt,omitempty"`
+}
+
+type statusBooksOutput struct {
+	Books []statusBook `json:"books"`
+	Count int          `json:"count"`
+}
+
+// get_reading_stats tool (no input needed)
+type getReadingStatsOutput struct {
+	TotalBooks       int                    `json:"total_books"`
+	FinishedBooks    int                    `json:"finished_books"`
+	BooksPerYear     map[string]int         `json:"books_per_year"`
+	BooksPerMonth    map[string]int         `json:"books_per_month"`
+	BooksPerLanguage map[string]int         `json:"books_per_language"`
+	TopAuthors       []stats.AuthorCount    `json:"top_authors"`
+	BooksByStatus    map[string]int         `json:"books_by_status"`
+	BooksPerGenre    map[string]int         `json:"books_per_genre"` // genre ID -> books
+	CurrentReads     []statusBook           `json:"current_reads"`
+	AbandonmentRate  float64                `json:"abandonment_rate"` // abandoned / (finished + abandoned)
+	Pace             stats.Pace             `json:"pace"`
+	YearOverYear     []stats.YearComparison `json:"year_over_year"`
 }
 
 // ============================================
@@ -94,12 +232,14 @@ type socialInfo struct {
 // ============================================
 
 type BookshelfTools struct {
-	books     []model.Book
-	portfolio *portfolio.Portfolio
+	bookRepo     deps.BookRepository
+	noteSearcher deps.NoteSearcher
+	portfolio    *portfolio.Portfolio
+	conversation *conversationState // recommendation history per session (nil: none)
 }
 
-func NewBookshelfTools(books []model.Book, p *portfolio.Portfolio) *BookshelfTools {
-	return &BookshelfTools{books: books, portfolio: p}
+func NewBookshelfTools(bookRepo deps.BookRepository, noteSearcher deps.NoteSearcher, p *portfolio.Portfolio) *BookshelfTools {
+	return &BookshelfTools{bookRepo: bookRepo, noteSearcher: noteSearcher, portfolio: p}
 }
 
 // ============================================
@@ -107,29 +247,23 @@ func NewBookshelfTools(books []model.Book, p *portfolio.Portfolio) *BookshelfToo
 // ============================================
 
 func (t *BookshelfTools) searchBooks(ctx tool.Context, input searchBooksInput) (searchBooksOutput, error) {
-	log.Printf("[TOOL] search_books called with query: %s", input.Query)
-	query := strings.ToLower(input.Query)
+	log.Printf("[TOOL] search_books called with query: %s tag: %s", input.Query, input.Tag)
 	var results []bookSummary
 
-	for _, book := range t.books {
-		if strings.Contains(strings.ToLower(book.Title), query) ||
-			strings.Contains(strings.ToLower(book.Author), query) ||
-			strings.Contains(strings.ToLower(book.PrivateNotes), query) {
-			// メモの抜粋を作成（最大200文字、rune単位で切る）
-			notesExcerpt := book.PrivateNotes
-			runes := []rune(notesExcerpt)
-			if len(runes) > 200 {
-				notesExcerpt = string(runes[:200]) + "..."
-			}
-			notesExcerpt = "<private_notes>" + sanitize.Notes(notesExcerpt) + "</private_notes>"
-			results = append(results, bookSummary{
-				ID:           book.ID,
-				Title:        book.Title,
-				Author:       book.Author,
-				Link:         book.Link,
-				NotesExcerpt: notesExcerpt,
-			})
-		}
+	for _, result := range t.searchWithTag(input.Query, input.Tag) {
+		book := result.Book
+		results = append(results, bookSummary{
+			ID:           book.ID,
+			Title:        book.Title,
+			Author:       book.Author,
+			Link:         book.Link,
+			NotesExcerpt: notesExcerpt(book.PrivateNotes),
+			Status:       book.ReadingStatus(),
+			Tags:         sanitizeLabels(book.Tags),
+			Genres:       book.Genres,
+			Score:        math.Round(result.Score*100) / 100,
+			Fuzzy:        result.Fuzzy,
+		})
 	}
 
 	log.Printf("[TOOL] search_books found %d results", len(results))
@@ -138,62 +272,366 @@ func (t *BookshelfTools) searchBooks(ctx tool.Context, input searchBooksInput) (
 
 func (t *BookshelfTools) getBookDetails(ctx tool.Context, input getBookDetailsInput) (getBookDetailsOutput, error) {
 	log.Printf("[TOOL] get_book_details called with book_id: %s", input.BookID)
-	for _, book := range t.books {
-		if book.ID == input.BookID {
-			log.Printf("[TOOL] get_book_details found: %s", book.Title)
-			return getBookDetailsOutput{
-				ID:         book.ID,
-				Title:      book.Title,
-				Author:     book.Author,
-				Link:       book.Link,
-				FinishedAt: book.FinishedAt,
-				Notes:      "<private_notes>" + sanitize.Notes(book.PrivateNotes) + "</private_notes>",
-			}, nil
-		}
+	if book := t.findBook("get_book_details", input.BookID); book != nil {
+		log.Printf("[TOOL] get_book_details found: %s", book.Title)
+		return getBookDetailsOutput{
+			ID:         book.ID,
+			Title:      book.Title,
+			Author:     book.Author,
+			Link:       book.Link,
+			FinishedAt: book.FinishedAt,
+			Status:     book.ReadingStatus(),
+			StartedAt:  book.StartedAt,
+			Progress:   book.Progress.Percentage(),
+			Rating:     book.Rating,
+			Notes:      "<private_notes>" + sanitize.Notes(book.PrivateNotes) + "</private_notes>",
+			Highlights: toHighlightInfo(book.ID, book.Highlights),
+		}, nil
 	}
 	log.Printf("[TOOL] get_book_details: book not found")
 	return getBookDetailsOutput{Error: "本が見つかりません"}, nil
 }
 
-// Empty input struct for tools with no parameters
-type emptyInput struct{}
+func (t *BookshelfTools) semanticSearchBooks(ctx tool.Context, input semanticSearchInput) (semanticSearchOutput, error) {
+	log.Printf("[TOOL] semantic_search_books called with query: %s", input.Query)
+	matches, err := t.noteSearcher.SearchNotes(ctx, input.Query, semanticSearchLimit)
+	if err != nil {
+		log.Printf("[TOOL] semantic_search_books failed: %v", err)
+		return semanticSearchOutput{}, nil
+	}
 
-func (t *BookshelfTools) getReadingStats(ctx tool.Context, _ emptyInput) (getReadingStatsOutput, error) {
-	log.Printf("[TOOL] get_reading_stats called")
-	yearCount := make(map[string]int)
-	authorCountMap := make(map[string]int)
-
-	for _, book := range t.books {
-		// Count by year
-		if len(book.FinishedAt) >= 4 {
-			year := book.FinishedAt[:4]
-			yearCount[year]++
+	var results []semanticMatch
+	for _, m := range matches {
+		book := t.bookRepo.GetByID(m.BookID)
+		if book == nil {
+			continue
+		}
+		results = append(results, semanticMatch{
+			ID:      book.ID,
+			Title:   book.Title,
+			Author:  book.Author,
+			Link:    book.Link,
+			Passage: "<private_notes>" + sanitize.Notes(m.Text) + "</private_notes>",
+			Score:   math.Round(m.Score*100) / 100,
+		})
+	}
+
+	log.Printf("[TOOL] semantic_search_books found %d results", len(results))
+	return semanticSearchOutput{Books: results, Count: len(results)}, nil
+}
+
+// searchWithTag runs the ranked search and keeps the books with the tag.
+// Without a query every book with the tag matches, in shelf order.
+func (t *BookshelfTools) searchWithTag(query, tag string) []search.Result {
+	if strings.TrimSpace(tag) == "" {
+		return t.bookRepo.Search(query)
+	}
+
+	var candidates []search.Result
+	if strings.TrimSpace(query) == "" {
+		for _, book := range t.bookRepo.GetAll() {
+			candidates = append(candidates, search.Result{Book: book})
+		}
+	} else {
+		// Every match, so tagged books ranked below the top results are kept
+		candidates = t.bookRepo.SearchAll(query)
+	}
+
+	var results []search.Result
+	for _, result := range candidates {
+		if model.HasLabel(result.Book.Tags, tag) {
+			results = append(results, result)
+		}
+		if len(results) == search.DefaultLimit {
+			break
+		}
+	}
+	return results
+}
+
+// sanitizeLabels sanitizes owner-written tags like notes
+func sanitizeLabels(labels []string) []string {
+	var result []string
+	for _, label := range labels {
+		result = append(result, sanitize.Notes(label))
+	}
+	return result
+}
+
+// notesExcerpt wraps the first 200 runes of the notes for tool output
+func notesExcerpt(notes string) string {
+	// メモの抜粋を作成（最大200文字、rune単位で切る）
+	runes := []rune(notes)
+	if len(runes) > 200 {
+		notes = string(runes[:200]) + "..."
+	}
+	return "<private_notes>" + sanitize.Notes(notes) + "</private_notes>"
+}
+
+func (t *BookshelfTools) getCurrentReads(ctx tool.Context, _ emptyInput) (statusBooksOutput, error) {
+	log.Printf("[TOOL] get_current_reads called")
+	books := t.booksWithStatus(model.StatusReading)
+	log.Printf("[TOOL] get_current_reads found %d books", len(books))
+	return statusBooksOutput{Books: books, Count: len(books)}, nil
+}
+
+func (t *BookshelfTools) getReadingList(ctx tool.Context, _ emptyInput) (statusBooksOutput, error) {
+	log.Printf("[TOOL] get_reading_list called")
+	books := t.booksWithStatus(model.StatusToRead)
+	log.Printf("[TOOL] get_reading_list found %d books", len(books))
+	return statusBooksOutput{Books: books, Count: len(books)}, nil
+}
+
+// booksWithStatus lists the books with the given status in shelf order
+func (t *BookshelfTools) booksWithStatus(status string) []statusBook {
+	var result []statusBook
+	for _, book := range t.bookRepo.GetAll() {
+		if book.ReadingStatus() == status {
+			result = append(result, toStatusBook(book))
+		}
+	}
+	return result
+}
+
+func toStatusBook(book model.Book) statusBook {
+	sb := statusBook{
+		ID:        book.ID,
+		Title:     book.Title,
+		Author:    book.Author,
+		Link:      book.Link,
+		StartedAt: book.StartedAt,
+		Progress:  book.Progress.Percentage(),
+	}
+	if book.Progress != nil {
+		sb.Page = book.Progress.Page
+	}
+	if book.PrivateNotes != "" {
+		sb.NotesExcerpt = notesExcerpt(book.PrivateNotes)
+	}
+	return sb
+}
+
+func (t *BookshelfTools) getBooksByAuthor(ctx tool.Context, input getBooksByAuthorInput) (getBooksByAuthorOutput, error) {
+	log.Printf("[TOOL] get_books_by_author called with author: %s", input.Author)
+
+	books := t.bookRepo.GetAll()
+	author, candidates, ok := authors.Resolve(authors.Directory(books), input.Author)
+	if !ok {
+		output := getBooksByAuthorOutput{Books: []authorBook{}, Error: "この著者の本は本棚にありません"}
+		if len(candidates) > 0 {
+			output.Error = "複数の著者が当てはまります"
+			for _, c := range candidates {
+				output.Candidates = append(output.Candidates, sanitize.Notes(c.Name))
+			}
 		}
-		// Count by author
-		authorCountMap[book.Author]++
+		log.Printf("[TOOL] get_books_by_author: not resolved (%d candidates)", len(candidates))
+		return output, nil
+	}
+
+	output := getBooksByAuthorOutput{Author: sanitize.Notes(author.Name), Books: []authorBook{}}
+	for _, book := range authors.BooksBy(books, author) {
+		ab := authorBook{
+			ID:         book.ID,
+			Title:      book.Title,
+			Link:       book.Link,
+			Status:     book.ReadingStatus(),
+			FinishedAt: book.FinishedAt,
+		}
+		if book.PrivateNotes != "" {
+			ab.NotesExcerpt = notesExcerpt(book.PrivateNotes)
+		}
+		output.Books = append(output.Books, ab)
+	}
+	output.Count = len(output.Books)
+
+	log.Printf("[TOOL] get_books_by_author resolved %q to %q: %d books", input.Author, author.Name, output.Count)
+	return output, nil
+}
+
+func (t *BookshelfTools) findSimilarBooks(ctx tool.Context, input findSimilarBooksInput) (findSimilarBooksOutput, error) {
+	log.Printf("[TOOL] find_similar_books called with book_id: %s, language: %s", input.BookID, input.Language)
+	book := t.findBook("find_similar_books", input.BookID)
+	if book == nil {
+		log.Printf("[TOOL] find_similar_books: book not found")
+		return findSimilarBooksOutput{Books: []similarBook{}, Error: "本が見つかりません"}, nil
+	}
+	language := input.Language
+	if language != "" && language != "ja" && language != "en" {
+		return findSimilarBooksOutput{Books: []similarBook{}, Error: "language は ja か en を指定してください"}, nil
 	}
 
-	// Get top authors (sort by count, take top 5)
-	var topAuthors []authorCount
-	for author, count := range authorCountMap {
-		topAuthors = append(topAuthors, authorCount{Author: author, Count: count})
+	var recommended []string
+	if t.conversation != nil {
+		recommended = t.conversation.recommended(ctx.SessionID())
 	}
-	// Simple sort (bubble sort for small data)
-	for i := 0; i < len(topAuthors); i++ {
-		for j := i + 1; j < len(topAuthors); j++ {
-			if topAuthors[j].Count > topAuthors[i].Count {
-				topAuthors[i], topAuthors[j] = topAuthors[j], topAuthors[i]
+
+	output := findSimilarBooksOutput{Books: []similarBook{}}
+	for _, m := range t.bookRepo.Related(book.ID) {
+		if language != "" && m.Book.Language != language {
+			continue
+		}
+		if slices.Contains(recommended, m.Book.ID) {
+			output.Excluded = append(output.Excluded, m.Book.ID)
+			continue
+		}
+		output.Books = append(output.Books, similarBook{
+			ID:      m.Book.ID,
+			Title:   m.Book.Title,
+			Author:  m.Book.Author,
+			Link:    m.Book.Link,
+			Status:  m.Book.ReadingStatus(),
+			Reasons: sanitizeLabels(slices.Concat(m.Reasons, m.NoteReasons)), // reasons quote notes terms and tags
+			Score:   m.Score,
+		})
+		if len(output.Books) == related.DefaultLimit {
+			break
+		}
+	}
+	output.Count = len(output.Books)
+
+	log.Printf("[TOOL] find_similar_books found %d books for %s (language=%s, excluded=%v)", output.Count, book.ID, language, output.Excluded)
+	return output, nil
+}
+
+// bookIDShape matches input that is meant as a book ID, even a wrong one
+var bookIDShape = regexp.MustCompile(`(?i)^book-\d+$`)
+
+// findBook looks up a book by ID, falling back to a title match when the model
+// passes a title instead of a book-NNN ID. The title must match exactly up to
+// case, width, kana and spacing, so a mistyped ID or a loose title never
+// resolves to an unrelated book. caller is the tool name for the log.
+func (t *BookshelfTools) findBook(caller, idOrTitle string) *model.Book {
+	idOrTitle = strings.TrimSpace(idOrTitle)
+	if book := t.bookRepo.GetByID(idOrTitle); book != nil {
+		return book
+	}
+	if bookIDShape.MatchString(idOrTitle) {
+		return nil
+	}
+	key := titleKey(idOrTitle)
+	if key == "" {
+		return nil
+	}
+	for _, book := range t.bookRepo.GetAll() {
+		if titleKey(book.Title) == key {
+			log.Printf("[TOOL] %s resolved %q to %s by title", caller, idOrTitle, book.ID)
+			return &book
+		}
+	}
+	return nil
+}
+
+// titleKey normalizes a title for findBook (case, width, kana, spacing)
+func titleKey(title string) string {
+	return strings.Join(strings.Fields(search.Normalize(title)), "")
+}
+
+func (t *BookshelfTools) getBookQuotes(ctx tool.Context, input getBookQuotesInput) (getBookQuotesOutput, error) {
+	log.Printf("[TOOL] get_book_quotes called with book_id: %s keyword: %s", input.BookID, input.Keyword)
+
+	var books []model.Book
+	if input.BookID != "" {
+		book := t.findBook("get_book_quotes", input.BookID)
+		if book == nil {
+			log.Printf("[TOOL] get_book_quotes: book not found")
+			return getBookQuotesOutput{Error: "本が見つかりません"}, nil
+		}
+		books = []model.Book{*book}
+	} else {
+		books = t.bookRepo.GetAll()
+	}
+
+	var quotes []bookQuote
+	for _, book := range books {
+		for i, h := range book.Highlights {
+			// Note-only entries and bookmarks have no passage to quote
+			if strings.TrimSpace(h.Text) == "" || !highlightMatches(h, input.Keyword) {
+				continue
+			}
+			quotes = append(quotes, bookQuote{
+				BookID:        book.ID,
+				Title:         book.Title,
+				Link:          book.Link,
+				highlightInfo: newHighlightInfo(book.ID, i, h),
+			})
+			if len(quotes) == quotesLimit {
+				break
 			}
 		}
+		if len(quotes) == quotesLimit {
+			break
+		}
+	}
+
+	log.Printf("[TOOL] get_book_quotes found %d quotes", len(quotes))
+	return getBookQuotesOutput{Quotes: quotes, Count: len(quotes)}, nil
+}
+
+// highlightMatches reports whether the keyword appears in the passage, the comment or a tag
+func highlightMatches(h model.Highlight, keyword string) bool {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return true
+	}
+	for _, text := range append([]string{h.Text, h.Note}, h.Tags...) {
+		if strings.Contains(strings.ToLower(text), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// toHighlightInfo sanitizes highlights the same way as notes (they are external text too)
+func toHighlightInfo(bookID string, highlights []model.Highlight) []highlightInfo {
+	var result []highlightInfo
+	for i, h := range highlights {
+		result = append(result, newHighlightInfo(bookID, i, h))
 	}
-	if len(topAuthors) > 5 {
-		topAuthors = topAuthors[:5]
+	return result
+}
+
+func newHighlightInfo(bookID string, i int, h model.Highlight) highlightInfo {
+	info := highlightInfo{
+		Text:     sanitize.Notes(h.Text),
+		Location: h.Location,
+		Page:     h.Page,
+		Note:     sanitize.Notes(h.Note),
+		Tags:     sanitizeLabels(h.Tags),
+	}
+	if strings.TrimSpace(h.Text) != "" {
+		info.Quote = model.QuoteRef(bookID, i+1)
+	}
+	return info
+}
+
+// Empty input struct for tools with no parameters
+type emptyInput struct{}
+
+func (t *BookshelfTools) getReadingStats(ctx tool.Context, _ emptyInput) (getReadingStatsOutput, error) {
+	log.Printf("[TOOL] get_reading_stats called")
+	books := t.bookRepo.GetAll()
+	st := stats.Compute(books)
+
+	var currentReads []statusBook
+	for _, book := range books {
+		if book.ReadingStatus() == model.StatusReading {
+			currentReads = append(currentReads, toStatusBook(book))
+		}
 	}
 
 	result := getReadingStatsOutput{
-		TotalBooks:   len(t.books),
-		BooksPerYear: yearCount,
-		TopAuthors:   topAuthors,
+		TotalBooks:       st.TotalBooks,
+		FinishedBooks:    st.FinishedBooks,
+		BooksPerYear:     st.BooksPerYear,
+		BooksPerMonth:    st.BooksPerMonth,
+		BooksPerLanguage: st.BooksPerLanguage,
+		TopAuthors:       st.TopAuthors,
+		BooksByStatus:    st.BooksByStatus,
+		BooksPerGenre:    st.BooksPerGenre,
+		CurrentReads:     currentReads,
+		AbandonmentRate:  st.AbandonmentRate,
+		Pace:             st.Pace,
+		YearOverYear:     st.YearOverYear,
 	}
 	log.Printf("[TOOL] get_reading_stats returning: %d total books", result.TotalBooks)
 	return result, nil
@@ -234,7 +672,7 @@ func (t *BookshelfTools) getOwnerInfo(ctx tool.Context, _ emptyInput) (getOwnerI
 		Title:    sanitize.Notes(p.About.Title),
 		Projects: projects,
 		Skills: skillsInfo{
-			Backend:  p.Skills.Backend,  // String slices (low risk)
+			Backend:  p.Skills.Backend, // String slices (low risk)
 			Frontend: p.Skills.Frontend,
 		},
 		Social: social,
@@ -248,7 +686,7 @@ func (t *BookshelfTools) getOwnerInfo(ctx tool.Context, _ emptyInput) (getOwnerI
 func (t *BookshelfTools) BuildTools() ([]tool.Tool, error) {
 	searchTool, err := functiontool.New(functiontool.Config{
 		Name:        "search_books",
-		Description: "本を検索（タイトル、著者、キーワード）",
+		Description: "本を検索（タイトル、著者、キーワード、タグで絞り込み）。関連度の高い順に最大10件",
 	}, t.searchBooks)
 	if err != nil {
 		return nil, err
@@ -256,15 +694,55 @@ func (t *BookshelfTools) BuildTools() ([]tool.Tool, error) {
 
 	detailsTool, err := functiontool.New(functiontool.Config{
 		Name:        "get_book_details",
-		Description: "本のメモを取得。notesの内容だけを使って回答",
+		Description: "本のメモとハイライトを取得。notesとhighlightsの内容だけを使って回答",
 	}, t.getBookDetails)
 	if err != nil {
 		return nil, err
 	}
 
+	quotesTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_book_quotes",
+		Description: "本のハイライト（引用）をキーワードで検索。引用するときは text を書き換えず、quote の [quote::book-id::n] を添える",
+	}, t.getBookQuotes)
+	if err != nil {
+		return nil, err
+	}
+
+	authorTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_books_by_author",
+		Description: "著者の本を本棚から取得（「この著者の本で他に何を読んだ？」向け）。著者名の表記ゆれは自動で解決。candidates が返ったらどの著者か確認する",
+	}, t.getBooksByAuthor)
+	if err != nil {
+		return nil, err
+	}
+
+	similarTool, err := functiontool.New(functiontool.Config{
+		Name:        "find_similar_books",
+		Description: "ある本に似た本を本棚から探す（メモ・タグ・著者の共通点から計算）。「似た本は？」「次に読むなら？」のおすすめはこの結果と reasons に基づいて答える。紹介済みの本は除外済み。language を指定したときだけその言語の本に絞る",
+	}, t.findSimilarBooks)
+	if err != nil {
+		return nil, err
+	}
+
+	currentReadsTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_current_reads",
+		Description: "今読んでいる本（進捗・読み始めた日付つき）を取得",
+	}, t.getCurrentReads)
+	if err != nil {
+		return nil, err
+	}
+
+	readingListTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_reading_list",
+		Description: "これから読む予定の本（積読リスト）を取得",
+	}, t.getReadingList)
+	if err != nil {
+		return nil, err
+	}
+
 	statsTool, err := functiontool.New(functiontool.Config{
 		Name:        "get_reading_stats",
-		Description: "読書統計を取得",
+		Description: "読書統計を取得（年別・月別・言語別の冊数、著者、状態別冊数、今読んでいる本、途中でやめた割合、読むペース、前年比）",
 	}, t.getReadingStats)
 	if err != nil {
 		return nil, err
@@ -278,5 +756,18 @@ func (t *BookshelfTools) BuildTools() ([]tool.Tool, error) {
 		return nil, err
 	}
 
-	return []tool.Tool{searchTool, detailsTool, statsTool, ownerTool}, nil
+	tools := []tool.Tool{searchTool, detailsTool, quotesTool, authorTool, similarTool, currentReadsTool, readingListTool, statsTool, ownerTool}
+
+	if t.noteSearcher != nil {
+		semanticTool, err := functiontool.New(functiontool.Config{
+			Name:        "semantic_search_books",
+			Description: "メモの内容を意味で検索（キーワードが一致しないテーマ・感想の質問向け）",
+		}, t.semanticSearchBooks)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, semanticTool)
+	}
+
+	return tools, nil
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/model"
 )
 
 var bookAnnotationRegex = regexp.MustCompile(`\[book::(.+?)::([^\]]+)\]`)
@@ -104,7 +105,7 @@ func (v *BookAnnotationValidator) CollectNotesContext(response string) (notesCon
 	for _, ann := range annotations {
 		book := v.bookRepo.GetByID(ann.BookID)
 		if book != nil {
-			notesBuilder.WriteString(fmt.Sprintf("<book>【%s のメモ】\n<notes>%s</notes></book>\n\n", book.Title, book.PrivateNotes))
+			notesBuilder.WriteString(fmt.Sprintf("<book>【%s のメモ】\n<notes>%s</notes>%s</book>\n\n", book.Title, book.PrivateNotes, highlightsContext(book.Highlights)))
 			formatsBuilder.WriteString(fmt.Sprintf("- [book::%s::%s]\n", ann.Title, ann.BookID))
 			foundBooks = append(foundBooks, book.Title)
 		}
@@ -112,3 +113,22 @@ func (v *BookAnnotationValidator) CollectNotesContext(response string) (notesCon
 
 	return notesBuilder.String(), formatsBuilder.String(), foundBooks
 }
+
+// highlightsContext formats highlights so the content check accepts answers quoting them
+func highlightsContext(highlights []model.Highlight) string {
+	if len(highlights) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n<highlights>")
+	for _, h := range highlights {
+		if h.Text != "" {
+			b.WriteString("\n- " + h.Text)
+		}
+		if h.Note != "" {
+			b.WriteString("\n  (メモ) " + h.Note)
+		}
+	}
+	b.WriteString("\n</highlights>")
+	return b.String()
+}
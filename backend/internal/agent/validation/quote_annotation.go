@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/model"
+)
+
+// quoteAnnotationRegex matches [quote::book-id::n], optionally preceded by the
+// quoted passage in 「」, “” or "" (the text the answer presents as a quote)
+var quoteAnnotationRegex = regexp.MustCompile(`(?:「([^」]*)」|“([^”]*)”|"([^"]*)")?\s*\[quote::([^:\]]+)::(\d+)\]`)
+
+// quoteEllipsisRegex splits a shortened quote into the parts that must appear verbatim
+var quoteEllipsisRegex = regexp.MustCompile(`…+|\.{3,}|・{3,}`)
+
+// QuoteAnnotation represents a parsed quote annotation from the response
+type QuoteAnnotation struct {
+	BookID string
+	Index  int    // 1-based position in the book's highlights
+	Quoted string // passage written before the annotation, if any
+
+	start, end int // byte range of the [quote::...] marker in the text
+}
+
+// QuoteAnnotationValidator validates [quote::book-id::n] annotations in responses.
+// The highlight must exist, and the annotation must follow a quoted passage that
+// matches the stored text. Annotations after a paraphrase are stripped.
+type QuoteAnnotationValidator struct {
+	bookRepo deps.BookRepository
+}
+
+// NewQuoteAnnotationValidator creates a new QuoteAnnotationValidator
+func NewQuoteAnnotationValidator(bookRepo deps.BookRepository) *QuoteAnnotationValidator {
+	return &QuoteAnnotationValidator{bookRepo: bookRepo}
+}
+
+// Name returns the validator name
+func (v *QuoteAnnotationValidator) Name() string {
+	return "QuoteAnnotationValidator"
+}
+
+// Validate checks every quote annotation against the stored highlights
+func (v *QuoteAnnotationValidator) Validate(ctx context.Context, input ValidationInput) ValidationResult {
+	annotations := ExtractQuoteAnnotations(input.Response)
+	if len(annotations) == 0 {
+		return OK()
+	}
+
+	log.Printf("[%s] Found %d quote annotation(s) to validate", v.Name(), len(annotations))
+
+	var unsupported []QuoteAnnotation
+	for _, ann := range annotations {
+		highlight, err := ResolveQuote(v.bookRepo, ann.BookID, ann.Index)
+		if err != nil {
+			log.Printf("[%s] HALLUCINATION: %v", v.Name(), err)
+			return Fail(err.Error())
+		}
+
+		ref := model.QuoteRef(ann.BookID, ann.Index)
+		switch {
+		case ann.Quoted == "":
+			log.Printf("[%s] UNQUOTED: %s does not follow a quoted passage", v.Name(), ref)
+			unsupported = append(unsupported, ann)
+		case !quoteMatches(ann.Quoted, highlight.Text):
+			log.Printf("[%s] MISQUOTE: %s claims %q but the highlight is %q",
+				v.Name(), ref, ann.Quoted, truncateForLog(highlight.Text, 100))
+			unsupported = append(unsupported, ann)
+		default:
+			log.Printf("[%s] Quote annotation valid: %s", v.Name(), ref)
+		}
+	}
+
+	if len(unsupported) == 0 {
+		return OK()
+	}
+
+	reason := fmt.Sprintf("%d quote annotation(s) without a matching quoted passage", len(unsupported))
+	corrected := stripQuoteAnnotations(input.Response, unsupported)
+	if strings.TrimSpace(corrected) == "" {
+		return Fail(reason)
+	}
+	return FailWithCorrection(reason, corrected)
+}
+
+// ExtractQuoteAnnotations extracts all [quote::book-id::n] annotations from text
+func ExtractQuoteAnnotations(text string) []QuoteAnnotation {
+	var annotations []QuoteAnnotation
+	for _, loc := range quoteAnnotationRegex.FindAllStringSubmatchIndex(text, -1) {
+		group := func(i int) string {
+			if loc[2*i] < 0 {
+				return ""
+			}
+			return text[loc[2*i]:loc[2*i+1]]
+		}
+		n, err := strconv.Atoi(group(5))
+		if err != nil {
+			continue
+		}
+		annotations = append(annotations, QuoteAnnotation{
+			BookID: group(4),
+			Index:  n,
+			Quoted: group(1) + group(2) + group(3),
+			start:  loc[8] - len("[quote::"),
+			end:    loc[1],
+		})
+	}
+	return annotations
+}
+
+// stripQuoteAnnotations removes the given annotations' markers from text,
+// keeping the surrounding prose
+func stripQuoteAnnotations(text string, annotations []QuoteAnnotation) string {
+	var b strings.Builder
+	last := 0
+	for _, ann := range annotations {
+		b.WriteString(strings.TrimRight(text[last:ann.start], " \t"))
+		last = ann.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// ResolveQuote returns the highlight a quote annotation refers to
+func ResolveQuote(bookRepo deps.BookRepository, bookID string, n int) (*model.Highlight, error) {
+	book := bookRepo.GetByID(bookID)
+	if book == nil {
+		return nil, fmt.Errorf("book ID '%s' does not exist", bookID)
+	}
+	if n < 1 || n > len(book.Highlights) {
+		return nil, fmt.Errorf("%s has no highlight %d (%d highlights)", bookID, n, len(book.Highlights))
+	}
+	if strings.TrimSpace(book.Highlights[n-1].Text) == "" {
+		return nil, fmt.Errorf("%s highlight %d is a note without a passage to quote", bookID, n)
+	}
+	return &book.Highlights[n-1], nil
+}
+
+// quoteMatches reports whether quoted appears verbatim in stored, ignoring
+// whitespace differences and allowing parts omitted with an ellipsis
+func quoteMatches(quoted, stored string) bool {
+	stored = normalizeQuote(stored)
+	found := false
+	for _, part := range quoteEllipsisRegex.Split(quoted, -1) {
+		part = normalizeQuote(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(stored, part) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func normalizeQuote(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,216 @@
+// Package authors builds the author directory of a shelf and resolves the
+// loosely written author names visitors and the agent use.
+package authors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/search"
+)
+
+// Author is one entry of the directory. Spellings that differ only in case,
+// spacing or periods are the same author; the most common spelling is the name.
+type Author struct {
+	Name      string   `json:"name"`
+	Slug      string   `json:"slug"`
+	Count     int      `json:"count"`     // books on the shelf
+	Languages []string `json:"languages"` // languages of their books, e.g. ["en", "ja"]
+
+	key string
+}
+
+// Directory lists the authors of books in order of first appearance
+func Directory(books []model.Book) []Author {
+	index := make(map[string]int)
+	spellings := make(map[string]map[string]int)
+	var result []Author
+
+	for _, book := range books {
+		seen := make(map[string]bool)
+		for _, name := range book.Authors() {
+			key := model.AuthorKey(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			i, ok := index[key]
+			if !ok {
+				i = len(result)
+				index[key] = i
+				spellings[key] = make(map[string]int)
+				result = append(result, Author{Name: name, key: key})
+			}
+			a := &result[i]
+			a.Count++
+			if book.Language != "" && !slices.Contains(a.Languages, book.Language) {
+				a.Languages = append(a.Languages, book.Language)
+			}
+
+			spellings[key][name]++
+			if spellings[key][name] > spellings[key][a.Name] {
+				a.Name = name
+			}
+		}
+	}
+
+	assignSlugs(result)
+	for i := range result {
+		if result[i].Languages == nil {
+			result[i].Languages = []string{}
+		}
+		slices.Sort(result[i].Languages)
+	}
+	return result
+}
+
+// assignSlugs gives every author a unique slug. Different names can share one
+// ("A-B" and "A B" are both "a-b"): the first author keeps it and the others get
+// "-2", "-3", ... in order of appearance, skipping slugs another author has.
+func assignSlugs(dir []Author) {
+	bases := make(map[string]bool, len(dir))
+	for i := range dir {
+		dir[i].Slug = Slug(dir[i].Name)
+		bases[dir[i].Slug] = true
+	}
+	used := make(map[string]bool, len(dir))
+	for i := range dir {
+		slug := dir[i].Slug
+		for n := 2; used[slug]; n++ {
+			if candidate := fmt.Sprintf("%s-%d", dir[i].Slug, n); !bases[candidate] && !used[candidate] {
+				slug = candidate
+			}
+		}
+		dir[i].Slug = slug
+		used[slug] = true
+	}
+}
+
+// Slug turns a name into a URL path segment: "Robert C. Martin" -> "robert-c-martin".
+// Names with Japanese characters keep them and drop spaces: "岸見 一郎" -> "岸見一郎".
+func Slug(name string) string {
+	if strings.ContainsFunc(name, isWide) {
+		return model.AuthorKey(name)
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(model.NormalizeAuthor(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		case r == '.' || r == '\'':
+			// "C." and "O'Reilly" stay one word
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// Find returns the author with the given slug, also accepting a name.
+// Slugs take precedence, so "a-b" finds the author with that slug, not "A-B".
+func Find(dir []Author, slugOrName string) (Author, bool) {
+	for _, a := range dir {
+		if a.Slug == slugOrName {
+			return a, true
+		}
+	}
+	key := model.AuthorKey(slugOrName)
+	for _, a := range dir {
+		if a.key == key {
+			return a, true
+		}
+	}
+	return Author{}, false
+}
+
+// Resolve finds the author a loosely written name refers to. It tries, in order:
+// the same name ignoring case and spacing (so Japanese names match with or without
+// the space between family and given name), the same kana ignoring katakana /
+// hiragana, the same words in another order ("Martin Robert C."), part of a name
+// ("Kleppmann", "岸見") and finally a typo-tolerant comparison.
+// When several authors fit equally well, ok is false and they are returned as candidates.
+func Resolve(dir []Author, query string) (match Author, candidates []Author, ok bool) {
+	key := model.AuthorKey(query)
+	if key == "" {
+		return Author{}, nil, false
+	}
+	if a, found := Find(dir, query); found {
+		return a, nil, true
+	}
+
+	kana := search.Normalize(key)
+	words := sortedWords(query)
+	var byKana, byWords, byPart []Author
+	for _, a := range dir {
+		switch {
+		case search.Normalize(a.key) == kana:
+			byKana = append(byKana, a)
+		case slices.Equal(sortedWords(a.Name), words):
+			byWords = append(byWords, a)
+		case len([]rune(key)) >= 2 && (strings.Contains(a.key, key) || strings.Contains(key, a.key)):
+			byPart = append(byPart, a)
+		}
+	}
+	for _, found := range [][]Author{byKana, byWords, byPart} {
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil, true
+		default:
+			return Author{}, found, false
+		}
+	}
+
+	best := 0.0
+	for _, a := range dir {
+		sim := search.Similarity(kana, search.Normalize(a.key))
+		switch {
+		case sim == 0 || sim < best:
+			continue
+		case sim > best:
+			best, candidates = sim, []Author{a}
+		default:
+			candidates = append(candidates, a)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil, true
+	}
+	return Author{}, candidates, false
+}
+
+// BooksBy returns the books a is an author of, in shelf order
+func BooksBy(books []model.Book, a Author) []model.Book {
+	var result []model.Book
+	for _, book := range books {
+		for _, name := range book.Authors() {
+			if model.AuthorKey(name) == a.key {
+				result = append(result, book)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// sortedWords returns the lowercased words of a name in alphabetical order
+func sortedWords(name string) []string {
+	words := strings.Fields(strings.ToLower(strings.NewReplacer(".", " ", ",", " ").Replace(model.NormalizeAuthor(name))))
+	slices.Sort(words)
+	return words
+}
+
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
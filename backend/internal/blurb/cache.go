@@ -0,0 +1,112 @@
+// Package blurb manages the public one-paragraph descriptions of books.
+//
+// A book's public blurb is the owner-approved Excerpt when set, otherwise an
+// AI-generated summary that passed Check and was cached for the current notes.
+// Private notes themselves are never published.
+package blurb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// CacheFile is the name of the summary cache kept next to a books.json
+const CacheFile = "blurbs.json"
+
+// Entry is a generated summary and the notes it was generated from
+type Entry struct {
+	Hash        string `json:"hash"`
+	Text        string `json:"text"`
+	Model       string `json:"model"`
+	GeneratedAt string `json:"generated_at"`
+}
+
+// Cache holds validated summaries keyed by book ID
+type Cache struct {
+	Books map[string]Entry `json:"books"`
+}
+
+// LoadCache reads the cache file; a missing file is an empty cache
+func LoadCache(path string) (*Cache, error) {
+	cache := &Cache{Books: make(map[string]Entry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cache.Books == nil {
+		cache.Books = make(map[string]Entry)
+	}
+	return cache, nil
+}
+
+// Save writes the cache atomically (temp file + rename)
+func (c *Cache) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode blurb cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blurbs-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blurb cache: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blurb cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blurb cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace blurb cache: %w", err)
+	}
+	return nil
+}
+
+// Get returns the cached summary of book if it was generated from the current notes
+func (c *Cache) Get(book model.Book) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	entry, ok := c.Books[book.ID]
+	if !ok || entry.Hash != SourceHash(book) || entry.Text == "" {
+		return "", false
+	}
+	return entry.Text, true
+}
+
+// Public returns the text that may be shown publicly for book, or "" if there is none
+func Public(book model.Book, cache *Cache) string {
+	if excerpt := strings.TrimSpace(book.Excerpt); excerpt != "" {
+		return excerpt
+	}
+	text, _ := cache.Get(book)
+	return text
+}
+
+// SourceHash identifies the inputs of a summary; editing them makes the cached summary stale
+func SourceHash(book model.Book) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{book.Title, book.Author, book.Language, book.PrivateNotes}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
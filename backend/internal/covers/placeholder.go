@@ -0,0 +1,158 @@
+package covers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Placeholder dimensions (2:3, the usual cover ratio)
+const (
+	placeholderWidth  = 400
+	placeholderHeight = 600
+)
+
+// Title wrapping, in half-width units (a CJK character counts as two)
+const (
+	titleLineUnits = 20
+	titleMaxLines  = 5
+	authorUnits    = 32
+)
+
+// Placeholder renders an SVG cover with the title and author on a background
+// colour derived from the book ID, so the same book always looks the same
+func Placeholder(id, title, author string) []byte {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	hue := h.Sum32() % 360
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`,
+		placeholderWidth, placeholderHeight, placeholderWidth, placeholderHeight)
+	b.WriteString(`<title>`)
+	xml.EscapeText(&b, []byte(title))
+	b.WriteString(`</title>`)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="hsl(%d, 45%%, 38%%)"/>`, hue)
+	fmt.Fprintf(&b, `<rect x="24" y="24" width="%d" height="%d" fill="none" stroke="hsl(%d, 45%%, 70%%)" stroke-width="2"/>`,
+		placeholderWidth-48, placeholderHeight-48, hue)
+
+	b.WriteString(`<g font-family="'Hiragino Sans', 'Noto Sans JP', sans-serif" fill="#fff" text-anchor="middle">`)
+	lines := wrapTitle(title)
+	y := 220 - (len(lines)-1)*22
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="32" font-weight="bold">`, placeholderWidth/2, y)
+		xml.EscapeText(&b, []byte(line))
+		b.WriteString(`</text>`)
+		y += 44
+	}
+	if author = strings.TrimSpace(author); author != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="20" opacity="0.85">`, placeholderWidth/2, placeholderHeight-72)
+		xml.EscapeText(&b, []byte(truncateUnits(author, authorUnits)))
+		b.WriteString(`</text>`)
+	}
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
+
+// wrapTitle breaks the title into lines of at most titleLineUnits, at spaces
+// for Latin text and anywhere for CJK, with an ellipsis past titleMaxLines
+func wrapTitle(title string) []string {
+	var lines []string
+	var line strings.Builder
+	units := 0
+	flush := func() {
+		if s := strings.TrimSpace(line.String()); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+		units = 0
+	}
+
+	for _, word := range splitWords(strings.TrimSpace(title)) {
+		w := textUnits(word)
+		if units > 0 && units+w > titleLineUnits {
+			flush()
+		}
+		for w > titleLineUnits {
+			// A single word longer than a line is broken where it overflows
+			head := truncateUnits(word, titleLineUnits)
+			head = strings.TrimSuffix(head, "…")
+			line.WriteString(head)
+			flush()
+			word = strings.TrimPrefix(word, head)
+			w = textUnits(word)
+		}
+		line.WriteString(word)
+		units += w
+	}
+	flush()
+
+	if len(lines) > titleMaxLines {
+		lines = lines[:titleMaxLines]
+		lines[titleMaxLines-1] = truncateUnits(lines[titleMaxLines-1]+"…", titleLineUnits)
+	}
+	return lines
+}
+
+// splitWords splits text into wrap points: each CJK character is its own word,
+// and Latin words keep their following space
+func splitWords(text string) []string {
+	var words []string
+	start := 0
+	for i, r := range text {
+		switch {
+		case isWide(r):
+			if start < i {
+				words = append(words, text[start:i])
+			}
+			words = append(words, string(r))
+			start = i + utf8.RuneLen(r)
+		case r == ' ':
+			words = append(words, text[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+// truncateUnits shortens s to at most n units, ending with an ellipsis when cut
+func truncateUnits(s string, n int) string {
+	if textUnits(s) <= n {
+		return s
+	}
+	units := 0
+	for i, r := range s {
+		units += runeUnits(r)
+		if units > n-1 {
+			return s[:i] + "…"
+		}
+	}
+	return s
+}
+
+func textUnits(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeUnits(r)
+	}
+	return n
+}
+
+func runeUnits(r rune) int {
+	if isWide(r) {
+		return 2
+	}
+	return 1
+}
+
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0xFF01 && r <= 0xFF60) || (r >= 0x3000 && r <= 0x303F)
+}
@@ -0,0 +1,113 @@
+// Package covers serves book cover images from a local directory.
+//
+// Covers are stored as <dir>/<book-id>.{jpg,jpeg,png,gif}. Thumbnails in the
+// widths listed in Widths are generated on first request and cached under
+// <dir>/.thumbs. Books without a cover get a generated SVG placeholder.
+package covers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// extensions are the image formats accepted as cover files
+var extensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+
+// Store finds cover files in a directory. The directory listing is cached
+// and re-read when the directory's modification time changes.
+type Store struct {
+	dir string
+
+	mu      sync.Mutex
+	files   map[string]string // book ID -> file path
+	modTime time.Time
+}
+
+// NewStore creates a store for dir; the directory does not have to exist
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the covers directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Find returns the cover file of the book, if there is one
+func (s *Store) Find(id string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.dir)
+	if err != nil {
+		s.files, s.modTime = nil, time.Time{}
+		return "", false
+	}
+	if s.files == nil || !info.ModTime().Equal(s.modTime) {
+		s.files = s.list()
+		s.modTime = info.ModTime()
+	}
+	path, ok := s.files[id]
+	return path, ok
+}
+
+// Version summarizes the cover files (names, sizes and mtimes) so catalog ETags
+// change when a cover is added, replaced or removed, and returns the newest
+// mtime. The hash is empty when there are no covers.
+func (s *Store) Version() (hash string, modTime time.Time) {
+	if s == nil {
+		return "", time.Time{}
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return "", time.Time{}
+	}
+	h := sha256.New()
+	found := false
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !extensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s %d %d\n", name, info.Size(), info.ModTime().UnixNano())
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+		found = true
+	}
+	if !found {
+		return "", time.Time{}
+	}
+	return hex.EncodeToString(h.Sum(nil)), modTime
+}
+
+// list maps book IDs to cover files; hidden files and directories are skipped
+func (s *Store) list() map[string]string {
+	files := make(map[string]string)
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return files
+	}
+	for _, e := range entries {
+		name := e.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if e.IsDir() || strings.HasPrefix(name, ".") || !extensions[ext] {
+			continue
+		}
+		files[strings.TrimSuffix(name, filepath.Ext(name))] = filepath.Join(s.dir, name)
+	}
+	return files
+}
@@ -0,0 +1,147 @@
+package covers
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// Widths are the thumbnail widths served with ?w=
+var Widths = []int{96, 192, 384}
+
+// thumbQuality is the JPEG quality of generated thumbnails
+const thumbQuality = 85
+
+// maxDecodePixels caps the size of a cover decoded for a thumbnail, so an
+// oversized image can't exhaust memory (about 100 MB as RGBA)
+const maxDecodePixels = 25_000_000
+
+var (
+	// ErrUnsupportedWidth is returned for widths not in Widths
+	ErrUnsupportedWidth = errors.New("unsupported thumbnail width")
+	// ErrImageTooLarge is returned for covers above maxDecodePixels
+	ErrImageTooLarge = errors.New("cover image too large to scale")
+)
+
+// Thumbnail returns a copy of the book's cover scaled to width, generating and
+// caching it under <dir>/.thumbs when missing or older than the cover. Covers
+// that are already narrow enough are returned as they are.
+func (s *Store) Thumbnail(id string, width int) (string, error) {
+	if !slices.Contains(Widths, width) {
+		return "", fmt.Errorf("%w %d", ErrUnsupportedWidth, width)
+	}
+	src, ok := s.Find(id)
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+
+	thumb := filepath.Join(s.dir, ".thumbs", id+"-"+strconv.Itoa(width)+".jpg")
+	if info, err := os.Stat(thumb); err == nil && !info.ModTime().Before(srcInfo.ModTime()) {
+		return thumb, nil
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", src, err)
+	}
+	if cfg.Width <= width {
+		return src, nil
+	}
+	if cfg.Width*cfg.Height > maxDecodePixels {
+		return "", fmt.Errorf("%w: %s is %dx%d", ErrImageTooLarge, src, cfg.Width, cfg.Height)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", src, err)
+	}
+
+	if err := writeJPEG(thumb, scale(img, width)); err != nil {
+		return "", err
+	}
+	return thumb, nil
+}
+
+// scale resizes img to width (keeping the aspect ratio) by averaging the
+// source pixels under each target pixel, which is enough for downscaling.
+// Transparent areas are flattened onto white.
+func scale(img image.Image, width int) *image.RGBA {
+	b := img.Bounds()
+	height := max(1, b.Dy()*width/b.Dx())
+
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*b.Dy()/height, max((y+1)*b.Dy()/height, y*b.Dy()/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*b.Dx()/width, max((x+1)*b.Dx()/width, x*b.Dx()/width+1)
+
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+3]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 0xff})
+		}
+	}
+	return dst
+}
+
+// writeJPEG encodes img to path atomically (temp file + rename), so concurrent
+// requests for the same thumbnail never see a partial file
+func writeJPEG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".thumb-*.jpg")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := jpeg.Encode(tmp, img, &jpeg.Options{Quality: thumbQuality}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace thumbnail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"talking-bookshelf/backend/internal/importer"
+	"talking-bookshelf/backend/internal/model"
+)
+
+var (
+	// ErrNoISBN is returned for books without an ISBN
+	ErrNoISBN = errors.New("book has no ISBN")
+	// ErrInvalidISBN is returned when the ISBN's length or check digit is wrong
+	ErrInvalidISBN = errors.New("invalid ISBN")
+)
+
+// Lookup validates isbn and fetches its metadata
+func Lookup(ctx context.Context, provider MetadataProvider, isbn string) (*Metadata, error) {
+	if strings.TrimSpace(isbn) == "" {
+		return nil, ErrNoISBN
+	}
+	isbn13 := model.ISBN13(isbn)
+	if isbn13 == "" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidISBN, isbn)
+	}
+	return provider.Lookup(ctx, isbn13)
+}
+
+// Book looks up the book's ISBN and fills in its details with Apply
+func Book(ctx context.Context, provider MetadataProvider, book *model.Book, force bool) ([]string, error) {
+	meta, err := Lookup(ctx, provider, book.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(book, meta, force), nil
+}
+
+// Apply copies metadata into book and returns the changes as "field: old -> new".
+// Fields the owner already set are kept unless force is true; empty metadata
+// never clears a field. A missing language is detected from the title.
+// Titles that would break the book's annotation are skipped and reported.
+func Apply(book *model.Book, meta *Metadata, force bool) []string {
+	var changes []string
+	setString := func(name string, field *string, value string) {
+		value = strings.TrimSpace(value)
+		if value == "" || *field == value || (*field != "" && !force) {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s: %q -> %q", name, *field, value))
+		*field = value
+	}
+	setInt := func(name string, field *int, value int) {
+		if value <= 0 || *field == value || (*field != 0 && !force) {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s: %d -> %d", name, *field, value))
+		*field = value
+	}
+
+	if title := strings.TrimSpace(meta.Title); model.LinkSafeTitle(title) {
+		setString("title", &book.Title, title)
+	} else if book.Title != title {
+		changes = append(changes, fmt.Sprintf("title: skipped %q (\"::\" or \"]\" breaks [book::title::id] annotations)", title))
+	}
+	setString("author", &book.Author, strings.Join(meta.Authors, ", "))
+	setInt("publish_year", &book.PublishYear, meta.PublishYear)
+	setInt("page_count", &book.PageCount, meta.PageCount)
+	setString("cover", &book.Cover, meta.Cover)
+	setString("language", &book.Language, meta.Language)
+	if book.Language == "" {
+		setString("language", &book.Language, importer.DetectLanguage(book.Title))
+	}
+
+	if len(meta.Subjects) > 0 && !slices.Equal(book.Subjects, meta.Subjects) && (len(book.Subjects) == 0 || force) {
+		changes = append(changes, fmt.Sprintf("subjects: %q -> %q", book.Subjects, meta.Subjects))
+		book.Subjects = slices.Clone(meta.Subjects)
+	}
+
+	book.Link = model.BookLink(book.Title, book.ID)
+	return changes
+}
@@ -0,0 +1,121 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a stored lookup result
+type cacheEntry struct {
+	Provider  string   `json:"provider"`
+	FetchedAt string   `json:"fetched_at"`
+	Metadata  Metadata `json:"metadata"`
+}
+
+// cacheFile is the on-disk metadata cache
+type cacheFile struct {
+	Books map[string]cacheEntry `json:"books"` // keyed by ISBN-13
+}
+
+// Cached wraps a provider with a JSON file cache so each ISBN is fetched once.
+// Only found records are cached; misses are retried on the next lookup.
+type Cached struct {
+	provider MetadataProvider
+	path     string
+
+	mu    sync.Mutex
+	books map[string]cacheEntry
+}
+
+// NewCached loads the cache at path (a missing file is an empty cache)
+func NewCached(provider MetadataProvider, path string) (*Cached, error) {
+	c := &Cached{provider: provider, path: path, books: make(map[string]cacheEntry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.Books != nil {
+		c.books = file.Books
+	}
+	return c, nil
+}
+
+// Name returns the wrapped provider's name
+func (c *Cached) Name() string {
+	return c.provider.Name()
+}
+
+// Lookup returns the cached record for isbn, fetching and storing it on a miss.
+// Records from another provider count as a miss. A cache write failure is
+// logged; the fetched record is still returned.
+func (c *Cached) Lookup(ctx context.Context, isbn string) (*Metadata, error) {
+	c.mu.Lock()
+	entry, ok := c.books[isbn]
+	c.mu.Unlock()
+	if ok && entry.Provider == c.provider.Name() {
+		meta := entry.Metadata
+		return &meta, nil
+	}
+
+	meta, err := c.provider.Lookup(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.books[isbn] = cacheEntry{
+		Provider:  c.provider.Name(),
+		FetchedAt: time.Now().UTC().Format(time.RFC3339),
+		Metadata:  *meta,
+	}
+	if err := c.save(); err != nil {
+		log.Printf("[ENRICH] %v", err)
+	}
+	return meta, nil
+}
+
+// save writes the cache atomically (temp file + rename). Callers hold mu.
+func (c *Cached) save() error {
+	data, err := json.MarshalIndent(cacheFile{Books: c.books}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".metadata-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metadata cache: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metadata cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metadata cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace metadata cache: %w", err)
+	}
+	return nil
+}
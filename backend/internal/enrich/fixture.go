@@ -0,0 +1,58 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// Fixture serves metadata from a JSON file instead of the network, for
+// offline use and tests. The file maps ISBNs (any hyphenation, ISBN-10 or
+// ISBN-13) to Metadata objects.
+type Fixture struct {
+	books map[string]Metadata
+}
+
+// LoadFixture reads a fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var entries map[string]Metadata
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return NewFixture(entries)
+}
+
+// NewFixture creates a provider from metadata keyed by ISBN
+func NewFixture(entries map[string]Metadata) (*Fixture, error) {
+	books := make(map[string]Metadata, len(entries))
+	for isbn, meta := range entries {
+		key := model.ISBN13(isbn)
+		if key == "" {
+			return nil, fmt.Errorf("fixture has invalid ISBN %q", isbn)
+		}
+		meta.ISBN = key
+		books[key] = meta
+	}
+	return &Fixture{books: books}, nil
+}
+
+// Name returns the provider name
+func (f *Fixture) Name() string {
+	return "fixture"
+}
+
+// Lookup returns the fixture entry for isbn
+func (f *Fixture) Lookup(_ context.Context, isbn string) (*Metadata, error) {
+	meta, ok := f.books[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &meta, nil
+}
@@ -0,0 +1,124 @@
+package enrich
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOpenLibraryURL is the Open Library Books API endpoint
+const DefaultOpenLibraryURL = "https://openlibrary.org/api/books"
+
+// maxSubjects caps the subjects kept from Open Library, which can list dozens
+const maxSubjects = 10
+
+// yearRegex finds the year in publish dates like "2008", "Aug 1, 2008" or "2012年3月"
+var yearRegex = regexp.MustCompile(`\b(1[5-9]\d\d|20\d\d)\b`)
+
+// OpenLibrary looks up books with the Open Library Books API
+type OpenLibrary struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenLibrary creates a provider for baseURL (DefaultOpenLibraryURL when empty)
+func NewOpenLibrary(baseURL string) *OpenLibrary {
+	if baseURL == "" {
+		baseURL = DefaultOpenLibraryURL
+	}
+	return &OpenLibrary{baseURL: baseURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name returns the provider name
+func (o *OpenLibrary) Name() string {
+	return "openlibrary"
+}
+
+// openLibraryBook is the subset of the jscmd=data response we use
+type openLibraryBook struct {
+	Title         string `json:"title"`
+	PublishDate   string `json:"publish_date"`
+	NumberOfPages int    `json:"number_of_pages"`
+	Authors       []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Subjects []struct {
+		Name string `json:"name"`
+	} `json:"subjects"`
+	Cover struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// Lookup fetches the edition for isbn
+func (o *OpenLibrary) Lookup(ctx context.Context, isbn string) (*Metadata, error) {
+	key := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"data"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Open Library request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Open Library: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Open Library returned %s", resp.Status)
+	}
+
+	var result map[string]openLibraryBook
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse Open Library response: %w", err)
+	}
+	book, ok := result[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	meta := &Metadata{
+		ISBN:        isbn,
+		Title:       book.Title,
+		PublishYear: publishYear(book.PublishDate),
+		PageCount:   book.NumberOfPages,
+		Cover:       cmp.Or(book.Cover.Large, book.Cover.Medium, book.Cover.Small),
+	}
+	for _, a := range book.Authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			meta.Authors = append(meta.Authors, name)
+		}
+	}
+	for _, s := range book.Subjects {
+		if len(meta.Subjects) == maxSubjects {
+			break
+		}
+		if name := strings.TrimSpace(s.Name); name != "" {
+			meta.Subjects = append(meta.Subjects, name)
+		}
+	}
+	return meta, nil
+}
+
+// publishYear extracts the year from a free-form publish date (0 if none)
+func publishYear(date string) int {
+	m := yearRegex.FindStringSubmatch(date)
+	if m == nil {
+		return 0
+	}
+	year, _ := strconv.Atoi(m[1])
+	return year
+}
@@ -0,0 +1,67 @@
+// Package enrich fills in book details (title, authors, publish year, page
+// count, subjects, cover) from an ISBN lookup.
+//
+// Lookups go through a MetadataProvider: Open Library online, or a fixture
+// file for offline use. Fields the owner already set are kept unless forced.
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// CacheFile is the name of the lookup cache kept next to a books.json
+	CacheFile = "metadata.cache.json"
+	// DefaultFixtures is the METADATA_PROVIDER=fixture file
+	DefaultFixtures = "data/metadata.fixtures.json"
+)
+
+// ErrNotFound is returned when the provider has no record for the ISBN
+var ErrNotFound = errors.New("no metadata found for ISBN")
+
+// Metadata is what a provider knows about an edition
+type Metadata struct {
+	ISBN        string   `json:"isbn"` // ISBN-13 digits
+	Title       string   `json:"title,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	PublishYear int      `json:"publish_year,omitempty"`
+	PageCount   int      `json:"page_count,omitempty"`
+	Subjects    []string `json:"subjects,omitempty"`
+	Cover       string   `json:"cover,omitempty"`    // cover image URL
+	Language    string   `json:"language,omitempty"` // "ja" or "en" when the provider knows it
+}
+
+// MetadataProvider looks up an edition by ISBN
+type MetadataProvider interface {
+	// Name identifies the provider in logs and the cache
+	Name() string
+	// Lookup returns the metadata for isbn (ISBN-13 digits) or ErrNotFound
+	Lookup(ctx context.Context, isbn string) (*Metadata, error)
+}
+
+// NewMetadataProvider creates the ISBN lookup provider selected by
+// METADATA_PROVIDER (openlibrary by default). Returns nil for "none".
+// cachePath enables the lookup cache; fixture lookups are never cached.
+func NewMetadataProvider(cachePath string) (MetadataProvider, error) {
+	switch name := os.Getenv("METADATA_PROVIDER"); name {
+	case "", "openlibrary":
+		provider := NewOpenLibrary(os.Getenv("OPENLIBRARY_URL"))
+		if cachePath == "" {
+			return provider, nil
+		}
+		return NewCached(provider, cachePath)
+	case "fixture":
+		path := os.Getenv("METADATA_FIXTURES")
+		if path == "" {
+			path = DefaultFixtures
+		}
+		return LoadFixture(path)
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown METADATA_PROVIDER %q (expected openlibrary, fixture or none)", name)
+	}
+}
@@ -0,0 +1,172 @@
+// Package feed renders syndication feeds (Atom 1.0 and RSS 2.0) of the bookshelf.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Channel is a feed independent of the output format
+type Channel struct {
+	ID          string // Atom id (a URL or URN)
+	Title       string
+	Description string
+	Link        string // the site
+	Self        string // the feed itself
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is one entry of the feed
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Authors   []string
+	Summary   string // plain text; omitted when empty
+	Published time.Time
+	Image     string
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Authors   []atomPerson `xml:"author"`
+	Summary   *atomText    `xml:"summary,omitempty"`
+	Links     []atomLink   `xml:"link"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Atom renders the channel as an Atom 1.0 feed
+func Atom(ch Channel) ([]byte, error) {
+	f := atomFeed{
+		ID:       ch.ID,
+		Title:    ch.Title,
+		Subtitle: ch.Description,
+		Updated:  ch.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Href: ch.Self, Type: "application/atom+xml"},
+			{Rel: "alternate", Href: ch.Link, Type: "text/html"},
+		},
+	}
+	for _, item := range ch.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Updated:   item.Published.UTC().Format(time.RFC3339),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Rel: "alternate", Href: item.Link, Type: "text/html"}},
+		}
+		for _, name := range item.Authors {
+			entry.Authors = append(entry.Authors, atomPerson{Name: name})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Text: item.Summary}
+		}
+		if item.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Href: item.Image})
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+	return marshal(f)
+}
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XmlnsAtom string     `xml:"xmlns:atom,attr"`
+	XmlnsDC   string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomSelf  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type atomSelf struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the channel as an RSS 2.0 feed
+func RSS(ch Channel) ([]byte, error) {
+	f := rssFeed{
+		Version:   "2.0",
+		XmlnsAtom: "http://www.w3.org/2005/Atom",
+		XmlnsDC:   "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         ch.Title,
+			Link:          ch.Link,
+			Description:   ch.Description,
+			AtomLink:      atomSelf{Rel: "self", Href: ch.Self, Type: "application/rss+xml"},
+			LastBuildDate: ch.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, item := range ch.Items {
+		f.Channel.Items = append(f.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.ID},
+			Creator:     strings.Join(item.Authors, ", "),
+			Description: item.Summary,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return marshal(f)
+}
+
+func marshal(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
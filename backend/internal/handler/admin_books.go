@@ -0,0 +1,409 @@
+package handler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/lint"
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bookIDFormat is the ID shape the agent's [book::title::id] parsing expects
+var bookIDFormat = regexp.MustCompile(`^book-\d+$`)
+
+// AdminBookRequest is the body for POST and PUT /api/admin/books.
+// Link is not accepted; it is always derived from title and ID.
+type AdminBookRequest struct {
+	ID           string            `json:"id"`
+	Title        string            `json:"title" binding:"required"`
+	Author       string            `json:"author" binding:"required"`
+	ISBN         string            `json:"isbn"`
+	Cover        string            `json:"cover"`
+	FinishedAt   string            `json:"finished_at"`
+	PrivateNotes string            `json:"private_notes"`
+	Language     string            `json:"language" binding:"required,oneof=ja en"`
+	Highlights   []model.Highlight `json:"highlights"`
+	Excerpt      string            `json:"excerpt"`
+	Status       string            `json:"status" binding:"omitempty,oneof=to-read reading finished abandoned"`
+	StartedAt    string            `json:"started_at"`
+	Progress     *model.Progress   `json:"progress"`
+	Rating       int               `json:"rating" binding:"min=0,max=5"`
+	Tags         []string          `json:"tags"`
+	Genres       []string          `json:"genres"`
+	Collections  []string          `json:"collections"`
+	PublishYear  int               `json:"publish_year" binding:"min=0"`
+	PageCount    int               `json:"page_count" binding:"min=0"`
+	Subjects     []string          `json:"subjects"`
+}
+
+// AdminBookPatch is the body for PATCH /api/admin/books/:id (only set fields change)
+type AdminBookPatch struct {
+	Title        *string            `json:"title" binding:"omitempty,min=1"`
+	Author       *string            `json:"author" binding:"omitempty,min=1"`
+	ISBN         *string            `json:"isbn"`
+	Cover        *string            `json:"cover"`
+	FinishedAt   *string            `json:"finished_at"`
+	PrivateNotes *string            `json:"private_notes"`
+	Language     *string            `json:"language" binding:"omitempty,oneof=ja en"`
+	Highlights   *[]model.Highlight `json:"highlights"`
+	Excerpt      *string            `json:"excerpt"`
+	Status       *string            `json:"status" binding:"omitempty,oneof=to-read reading finished abandoned"`
+	StartedAt    *string            `json:"started_at"`
+	Progress     *model.Progress    `json:"progress"` // {} clears it
+	Rating       *int               `json:"rating" binding:"omitempty,min=0,max=5"`
+	Tags         *[]string          `json:"tags"`
+	Genres       *[]string          `json:"genres"`
+	Collections  *[]string          `json:"collections"`
+	PublishYear  *int               `json:"publish_year" binding:"omitempty,min=0"`
+	PageCount    *int               `json:"page_count" binding:"omitempty,min=0"`
+	Subjects     *[]string          `json:"subjects"`
+}
+
+func (r AdminBookRequest) toBook(id string) model.Book {
+	return model.Book{
+		ID:           id,
+		Title:        r.Title,
+		Author:       r.Author,
+		ISBN:         r.ISBN,
+		Cover:        r.Cover,
+		FinishedAt:   r.FinishedAt,
+		PrivateNotes: r.PrivateNotes,
+		Link:         model.BookLink(r.Title, id),
+		Language:     r.Language,
+		Highlights:   r.Highlights,
+		Excerpt:      r.Excerpt,
+		Status:       cmp.Or(r.Status, model.StatusFinished),
+		StartedAt:    r.StartedAt,
+		Progress:     r.Progress,
+		Rating:       r.Rating,
+		Tags:         r.Tags,
+		Genres:       r.Genres,
+		Collections:  r.Collections,
+		PublishYear:  r.PublishYear,
+		PageCount:    r.PageCount,
+		Subjects:     r.Subjects,
+	}
+}
+
+func (p AdminBookPatch) apply(book *model.Book) {
+	if p.Title != nil {
+		book.Title = *p.Title
+	}
+	if p.Author != nil {
+		book.Author = *p.Author
+	}
+	if p.ISBN != nil {
+		book.ISBN = *p.ISBN
+	}
+	if p.Cover != nil {
+		book.Cover = *p.Cover
+	}
+	if p.FinishedAt != nil {
+		book.FinishedAt = *p.FinishedAt
+	}
+	if p.PrivateNotes != nil {
+		book.PrivateNotes = *p.PrivateNotes
+	}
+	if p.Language != nil {
+		book.Language = *p.Language
+	}
+	if p.Highlights != nil {
+		book.Highlights = *p.Highlights
+	}
+	if p.Excerpt != nil {
+		book.Excerpt = *p.Excerpt
+	}
+	if p.Status != nil {
+		book.Status = *p.Status
+	}
+	if p.StartedAt != nil {
+		book.StartedAt = *p.StartedAt
+	}
+	if p.Progress != nil {
+		book.Progress = p.Progress
+		if *p.Progress == (model.Progress{}) {
+			book.Progress = nil
+		}
+	}
+	if p.Rating != nil {
+		book.Rating = *p.Rating
+	}
+	if p.Tags != nil {
+		book.Tags = *p.Tags
+	}
+	if p.Genres != nil {
+		book.Genres = *p.Genres
+	}
+	if p.Collections != nil {
+		book.Collections = *p.Collections
+	}
+	if p.PublishYear != nil {
+		book.PublishYear = *p.PublishYear
+	}
+	if p.PageCount != nil {
+		book.PageCount = *p.PageCount
+	}
+	if p.Subjects != nil {
+		book.Subjects = *p.Subjects
+	}
+	book.Link = model.BookLink(book.Title, book.ID)
+}
+
+// HandleAdminCreateBook adds a book (POST /api/admin/books).
+// The ID is generated when omitted and must be unique when given.
+func HandleAdminCreateBook(c *gin.Context) {
+	var req AdminBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if err := checkGenres(req.Genres); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	id := req.ID
+	if id == "" {
+		id = model.NextBookID(GetBooks())
+	} else if !bookIDFormat.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id must look like book-001",
+			"code":  "INVALID_BOOK_ID",
+		})
+		return
+	} else if GetBookByID(id) != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "A book with this id already exists",
+			"code":  "BOOK_ID_CONFLICT",
+		})
+		return
+	}
+
+	book := req.toBook(id)
+	if err := checkBook(book); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if !persistBook(c, book) {
+		return
+	}
+
+	log.Printf("[ADMIN] Created book %s (%s)", book.ID, book.Title)
+	c.JSON(http.StatusCreated, currentShelf(c).bookResponse(&book))
+}
+
+// HandleAdminReplaceBook replaces a book entirely (PUT /api/admin/books/:id)
+func HandleAdminReplaceBook(c *gin.Context) {
+	id := c.Param("id")
+
+	var req AdminBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if err := checkGenres(req.Genres); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if req.ID != "" && req.ID != id {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id in body does not match the URL",
+			"code":  "INVALID_BOOK_ID",
+		})
+		return
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if GetBookByID(id) == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+
+	book := req.toBook(id)
+	if err := checkBook(book); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if !persistBook(c, book) {
+		return
+	}
+
+	log.Printf("[ADMIN] Replaced book %s (%s)", book.ID, book.Title)
+	c.JSON(http.StatusOK, currentShelf(c).bookResponse(&book))
+}
+
+// HandleAdminUpdateBook changes selected fields of a book (PATCH /api/admin/books/:id)
+func HandleAdminUpdateBook(c *gin.Context) {
+	id := c.Param("id")
+
+	var patch AdminBookPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if patch.Genres != nil {
+		if err := checkGenres(*patch.Genres); err != nil {
+			respondInvalidBook(c, err)
+			return
+		}
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	existing := GetBookByID(id)
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+
+	book := *existing
+	patch.apply(&book)
+	if err := checkBook(book); err != nil {
+		respondInvalidBook(c, err)
+		return
+	}
+	if !persistBook(c, book) {
+		return
+	}
+
+	log.Printf("[ADMIN] Updated book %s (%s)", book.ID, book.Title)
+	c.JSON(http.StatusOK, currentShelf(c).bookResponse(&book))
+}
+
+// HandleAdminDeleteBook removes a book (DELETE /api/admin/books/:id)
+func HandleAdminDeleteBook(c *gin.Context) {
+	id := c.Param("id")
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if GetBookByID(id) == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+	remaining := slices.DeleteFunc(slices.Clone(GetBooks()), func(b model.Book) bool { return b.ID == id })
+	if !checkShelf(c, remaining) {
+		return
+	}
+
+	if err := currentBookWriter().Delete(id); err != nil {
+		if errors.Is(err, store.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		log.Printf("[ADMIN] Failed to delete book %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete book"})
+		return
+	}
+	if err := refreshBooks(); err != nil {
+		log.Printf("[ADMIN] Book %s deleted but refresh failed: %v", id, err)
+		respondRefreshFailed(c)
+		return
+	}
+
+	log.Printf("[ADMIN] Deleted book %s", id)
+	c.Status(http.StatusNoContent)
+}
+
+// persistBook saves the book through the configured store and republishes the
+// shelf so /api/books and the agent tools see the change. Callers hold reloadMu.
+func persistBook(c *gin.Context, book model.Book) bool {
+	books := slices.Clone(GetBooks())
+	if i := slices.IndexFunc(books, func(b model.Book) bool { return b.ID == book.ID }); i >= 0 {
+		books[i] = book
+	} else {
+		books = append(books, book)
+	}
+	if !checkShelf(c, books) {
+		return false
+	}
+
+	if err := currentBookWriter().Save(book); err != nil {
+		log.Printf("[ADMIN] Failed to save book %s: %v", book.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save book"})
+		return false
+	}
+	if err := refreshBooks(); err != nil {
+		log.Printf("[ADMIN] Book %s saved but refresh failed: %v", book.ID, err)
+		respondRefreshFailed(c)
+		return false
+	}
+	return true
+}
+
+// checkShelf validates the shelf as it would be after a write, so a change the
+// reload would reject (e.g. deleting the last book) is refused before touching disk
+func checkShelf(c *gin.Context, books []model.Book) bool {
+	if err := store.ValidateBooks(books); err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "The shelf would become invalid: " + err.Error(),
+			"code":  "INVALID_SHELF",
+		})
+		return false
+	}
+	return true
+}
+
+// respondRefreshFailed reports a write that reached the store but could not be
+// published, so clients don't assume readers already see it
+func respondRefreshFailed(c *gin.Context) {
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "The change was saved but the shelf could not be reloaded",
+		"code":  "REFRESH_FAILED",
+	})
+}
+
+func currentBookWriter() deps.BookWriter {
+	bookRepoMu.RLock()
+	defer bookRepoMu.RUnlock()
+	return bookWriter
+}
+
+// checkGenres rejects genres outside the controlled list
+func checkGenres(genres []string) error {
+	for _, id := range genres {
+		if !model.ValidGenre(id) {
+			return fmt.Errorf("unknown genre %q (see GET /api/genres)", id)
+		}
+	}
+	return nil
+}
+
+// checkBook rejects a book that `bookshelf lint` would report errors for
+// (e.g. a title with "::" or "]", a malformed date or a bad ISBN check digit),
+// so the admin API never writes data the rest of the app can't handle
+func checkBook(book model.Book) error {
+	var messages []string
+	for _, issue := range lint.CheckBook(book) {
+		if issue.Severity == lint.SeverityError {
+			messages = append(messages, issue.Message)
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+func respondInvalidBook(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": "Invalid book: " + err.Error(),
+		"code":  "INVALID_BOOK",
+	})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+
+	"talking-bookshelf/backend/internal/authors"
+	"talking-bookshelf/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorDetail is the body of GET /api/authors/:slug
+type AuthorDetail struct {
+	authors.Author
+	Books []model.BookResponse `json:"books"`
+}
+
+// HandleGetAuthors lists the shelf's authors with book counts and languages,
+// most books first (GET /api/authors). lang selects the collation of ties.
+func HandleGetAuthors(c *gin.Context) {
+	dir := authors.Directory(currentShelf(c).books.GetAll())
+	col := collatorFor(c.Query("lang"))
+	sort.SliceStable(dir, func(i, j int) bool {
+		if dir[i].Count != dir[j].Count {
+			return dir[i].Count > dir[j].Count
+		}
+		return col.CompareString(dir[i].Name, dir[j].Name) < 0
+	})
+	if dir == nil {
+		dir = []authors.Author{}
+	}
+	c.JSON(http.StatusOK, dir)
+}
+
+// HandleGetAuthor returns an author and their books (GET /api/authors/:slug).
+// The author's name is accepted in place of the slug.
+func HandleGetAuthor(c *gin.Context) {
+	view := currentShelf(c)
+	books := view.books.GetAll()
+	author, ok := authors.Find(authors.Directory(books), c.Param("slug"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Author not found",
+			"code":  "AUTHOR_NOT_FOUND",
+		})
+		return
+	}
+
+	detail := AuthorDetail{Author: author, Books: []model.BookResponse{}}
+	for _, book := range authors.BooksBy(books, author) {
+		detail.Books = append(detail.Books, view.bookResponse(&book))
+	}
+	c.JSON(http.StatusOK, detail)
+}
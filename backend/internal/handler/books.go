@@ -1,80 +1,183 @@
 package handler
 
 import (
-	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"os"
-	"sort"
 	"sync"
+	"sync/atomic"
 
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/lint"
 	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/store"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// BooksJSONPath is the JSON data file (source of truth for the json store, import source for sqlite)
+	BooksJSONPath = "data/books.json"
+	// DefaultBooksDBPath is the SQLite database used when BOOK_STORE=sqlite and BOOKS_DB_PATH is unset
+	DefaultBooksDBPath = "data/books.db"
+)
+
+// bookRepo is the single book source shared by the HTTP handlers,
+// the agent tools and the validation pipeline
 var (
-	books     []model.Book
-	booksOnce sync.Once
+	bookRepo   deps.BookRepository
+	bookWriter deps.BookWriter
+	bookRepoMu sync.RWMutex
+	// bookStoreType is the BOOK_STORE backend chosen at startup ("json" or "sqlite")
+	bookStoreType string
+	// notesEncrypted is set when the store holds encrypted notes (no plaintext caches may be written)
+	notesEncrypted atomic.Bool
 )
 
-func loadBooks() {
-	booksOnce.Do(func() {
-		data, err := os.ReadFile("data/books.json")
+// InitBookRepository opens the configured book store. Must be called at startup
+// before InitBookshelfAgent so that every consumer receives the same instance.
+func InitBookRepository() error {
+	repo, writer, err := openBookRepository()
+	if err != nil {
+		return err
+	}
+
+	bookRepoMu.Lock()
+	bookRepo = repo
+	bookWriter = writer
+	bookStoreType = os.Getenv("BOOK_STORE")
+	if bookStoreType == "" {
+		bookStoreType = "json"
+	}
+	bookRepoMu.Unlock()
+
+	invalidateDataVersion()
+	CheckBookData()
+	return nil
+}
+
+// CheckBookData runs the same checks as `bookshelf lint` and logs what it finds.
+// Problems are reported but don't stop the server; ValidateBooks guards the fatal ones.
+func CheckBookData() {
+	var issues []lint.Issue
+	if usesJSONStore() {
+		fileIssues, _, err := lint.CheckFile(BooksJSONPath)
 		if err != nil {
+			log.Printf("[LINT] Failed to check %s: %v", BooksJSONPath, err)
 			return
 		}
-		json.Unmarshal(data, &books)
-	})
+		issues = fileIssues
+	} else {
+		issues = lint.CheckBooks(GetBooks())
+	}
+
+	for _, issue := range issues {
+		log.Printf("[LINT] %s", issue)
+	}
+	if len(issues) > 0 {
+		log.Printf("[LINT] %d issues in book data (run `go run ./cmd/bookshelf lint --fix` to repair fixable ones)", len(issues))
+	}
+}
+
+// openBookRepository selects the book store from BOOK_STORE ("json" or "sqlite")
+// and returns its repository together with the writer used by the admin API
+func openBookRepository() (deps.BookRepository, deps.BookWriter, error) {
+	notes, err := store.NotesPolicyFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch storeType := os.Getenv("BOOK_STORE"); storeType {
+	case "", "json":
+		books, err := store.LoadBooksJSON(BooksJSONPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		encrypted, err := notes.Open(books, BooksJSONPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		notesEncrypted.Store(encrypted)
+		log.Printf("[INFO] Book store: json path=%s books=%d encrypted_notes=%t", BooksJSONPath, len(books), encrypted)
+		return store.NewInMemoryBookRepository(books), store.NewJSONBookWriter(BooksJSONPath, notes.Key), nil
+
+	case "sqlite":
+		dbPath := os.Getenv("BOOKS_DB_PATH")
+		if dbPath == "" {
+			dbPath = DefaultBooksDBPath
+		}
+		repo, err := store.NewSQLiteBookRepository(dbPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		// One-shot import: seed an empty database from books.json
+		count, err := repo.Count()
+		if err != nil {
+			return nil, nil, err
+		}
+		if count == 0 {
+			imported, err := repo.ImportJSON(BooksJSONPath)
+			if err != nil {
+				log.Printf("[WARN] Failed to import %s into sqlite: %v", BooksJSONPath, err)
+			} else {
+				log.Printf("[INFO] Imported %d books from %s into sqlite", imported, BooksJSONPath)
+			}
+		}
+		if err := repo.OpenNotes(notes, dbPath); err != nil {
+			repo.Close()
+			return nil, nil, err
+		}
+		notesEncrypted.Store(repo.EncryptedNotes())
+		log.Printf("[INFO] Book store: sqlite path=%s", dbPath)
+		return repo, repo, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown BOOK_STORE %q (expected json or sqlite)", storeType)
+	}
+}
+
+// GetBookRepository returns the shared book repository
+func GetBookRepository() deps.BookRepository {
+	bookRepoMu.RLock()
+	defer bookRepoMu.RUnlock()
+	if bookRepo == nil {
+		// Not initialized (e.g. store failed to open): serve an empty shelf
+		return store.NewInMemoryBookRepository(nil)
+	}
+	return bookRepo
 }
 
 func GetBooks() []model.Book {
-	loadBooks()
-	return books
+	return GetBookRepository().GetAll()
 }
 
 func GetBookByID(id string) *model.Book {
-	loadBooks()
-	for _, book := range books {
-		if book.ID == id {
-			return &book
-		}
-	}
-	return nil
+	return GetBookRepository().GetByID(id)
 }
 
+// HandleGetBooks lists the shelf. Supports q (ranked search), author, language,
+// year/from/to (finished_at), sort and offset/limit; see parseBookListQuery.
+// The body stays a plain array; the total goes in X-Total-Count and pages in Link.
 func HandleGetBooks(c *gin.Context) {
-	loadBooks()
-	lang := c.Query("lang")
-
-	// Create a copy to avoid mutating the original slice
-	sortedBooks := make([]model.Book, len(books))
-	copy(sortedBooks, books)
-
-	// Sort by language priority: current language first
-	if lang != "" {
-		sort.SliceStable(sortedBooks, func(i, j int) bool {
-			iMatch := sortedBooks[i].Language == lang
-			jMatch := sortedBooks[j].Language == lang
-			if iMatch != jMatch {
-				return iMatch
-			}
-			return false
-		})
+	q, err := parseBookListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_QUERY"})
+		return
 	}
 
-	responses := make([]model.BookResponse, len(sortedBooks))
-	for i, book := range sortedBooks {
-		responses[i] = book.ToResponse()
-	}
-	c.JSON(http.StatusOK, responses)
+	items, total := listBooks(currentShelf(c), q)
+	setPaginationHeaders(c, q, total)
+	c.JSON(http.StatusOK, items)
 }
 
 func HandleGetBook(c *gin.Context) {
 	id := c.Param("id")
-	book := GetBookByID(id)
+	view := currentShelf(c)
+	book := view.books.GetByID(id)
 	if book == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
 		return
 	}
-	c.JSON(http.StatusOK, book.ToResponse())
+	c.JSON(http.StatusOK, view.bookResponse(book))
 }
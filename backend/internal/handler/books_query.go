@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"talking-bookshelf/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// MaxBooksPageSize caps the limit parameter of GET /api/books
+const MaxBooksPageSize = 100
+
+var yearParamRegex = regexp.MustCompile(`^\d{4}$`)
+
+// dateParamLayouts are the accepted from/to formats, keyed by length
+var dateParamLayouts = map[int]string{4: "2006", 7: "2006-01", 10: "2006-01-02"}
+
+// bookListQuery holds the query parameters of GET /api/books
+type bookListQuery struct {
+	Q          string   // ranked text search
+	Author     string   // case-insensitive substring
+	Language   string   // exact "ja" / "en"
+	Lang       string   // UI language: listed first and used for title collation
+	Year       string   // finished_at year
+	Status     []string // reading statuses (comma-separated), any of them matches
+	Tags       []string // tags (comma-separated), all of them must match
+	Genre      string   // genre ID from model.Genres
+	Collection string   // named collection, case-insensitive
+	From       string   // finished_at >= From (YYYY, YYYY-MM or YYYY-MM-DD)
+	To         string   // finished_at <= To (same formats, inclusive)
+	Sort       string   // finished_at, started_at, rating, title, author, relevance; "-" prefix for descending
+	Offset     int
+	Limit      int // 0 = no pagination
+}
+
+// bookListItem is one book in the GET /api/books response.
+// Score and Fuzzy are only present when searching with q.
+type bookListItem struct {
+	model.BookResponse
+	Score *float64 `json:"score,omitempty"`
+	Fuzzy *bool    `json:"fuzzy,omitempty"`
+}
+
+func parseBookListQuery(c *gin.Context) (bookListQuery, error) {
+	q := bookListQuery{
+		Q:        strings.TrimSpace(c.Query("q")),
+		Author:   strings.TrimSpace(c.Query("author")),
+		Language: c.Query("language"),
+		Lang:     c.Query("lang"),
+		Year:     c.Query("year"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Sort:     c.Query("sort"),
+	}
+	if status := c.Query("status"); status != "" {
+		q.Status = strings.Split(status, ",")
+	}
+	if tags := c.Query("tag"); tags != "" {
+		q.Tags = strings.Split(tags, ",")
+	}
+	q.Genre = c.Query("genre")
+	q.Collection = strings.TrimSpace(c.Query("collection"))
+
+	if q.Language != "" && q.Language != "ja" && q.Language != "en" {
+		return q, fmt.Errorf("language must be ja or en")
+	}
+	for _, status := range q.Status {
+		if !model.ValidStatus(status) {
+			return q, fmt.Errorf("status must be one of %s", strings.Join(model.Statuses, ", "))
+		}
+	}
+	if q.Genre != "" && !model.ValidGenre(q.Genre) {
+		return q, fmt.Errorf("unknown genre %q (see GET /api/genres)", q.Genre)
+	}
+	if q.Year != "" && !yearParamRegex.MatchString(q.Year) {
+		return q, fmt.Errorf("year must be YYYY")
+	}
+	if q.From != "" && !validDateParam(q.From) {
+		return q, fmt.Errorf("from must be YYYY, YYYY-MM or YYYY-MM-DD")
+	}
+	if q.To != "" && !validDateParam(q.To) {
+		return q, fmt.Errorf("to must be YYYY, YYYY-MM or YYYY-MM-DD")
+	}
+	switch strings.TrimPrefix(q.Sort, "-") {
+	case "", "finished_at", "started_at", "rating", "title", "author":
+	case "relevance":
+		if q.Q == "" {
+			return q, fmt.Errorf("sort=relevance requires q")
+		}
+	default:
+		return q, fmt.Errorf("sort must be one of finished_at, started_at, rating, title, author, relevance")
+	}
+
+	var err error
+	if q.Offset, err = intParam(c, "offset", 0); err != nil {
+		return q, err
+	}
+	if q.Limit, err = intParam(c, "limit", 0); err != nil {
+		return q, err
+	}
+	if q.Limit > MaxBooksPageSize {
+		return q, fmt.Errorf("limit must be at most %d", MaxBooksPageSize)
+	}
+	return q, nil
+}
+
+func validDateParam(value string) bool {
+	layout, ok := dateParamLayouts[len(value)]
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(layout, value)
+	return err == nil
+}
+
+func intParam(c *gin.Context, name string, def int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// listBooks applies search, filters and sorting, and returns the matching page and total
+func listBooks(view shelfView, q bookListQuery) ([]bookListItem, int) {
+	var items []bookListItem
+	if q.Q != "" {
+		// Filters and pages apply to every match, not just the top results
+		for _, result := range view.books.SearchAll(q.Q) {
+			score := math.Round(result.Score*100) / 100
+			fuzzy := result.Fuzzy
+			items = append(items, bookListItem{BookResponse: view.bookResponse(&result.Book), Score: &score, Fuzzy: &fuzzy})
+		}
+	} else {
+		for _, book := range view.books.GetAll() {
+			items = append(items, bookListItem{BookResponse: view.bookResponse(&book)})
+		}
+	}
+
+	filtered := items[:0]
+	for _, item := range items {
+		if q.matches(item.BookResponse) {
+			filtered = append(filtered, item)
+		}
+	}
+	items = filtered
+
+	sortBookItems(items, q)
+
+	total := len(items)
+	if q.Offset >= len(items) {
+		return []bookListItem{}, total
+	}
+	items = items[q.Offset:]
+	if q.Limit > 0 && len(items) > q.Limit {
+		items = items[:q.Limit]
+	}
+	return items, total
+}
+
+func (q bookListQuery) matches(b model.BookResponse) bool {
+	if q.Language != "" && b.Language != q.Language {
+		return false
+	}
+	if len(q.Status) > 0 && !slices.Contains(q.Status, b.Status) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !model.HasLabel(b.Tags, tag) {
+			return false
+		}
+	}
+	if q.Genre != "" && !slices.Contains(b.Genres, q.Genre) {
+		return false
+	}
+	if q.Collection != "" && !model.HasLabel(b.Collections, q.Collection) {
+		return false
+	}
+	if q.Author != "" && !strings.Contains(strings.ToLower(b.Author), strings.ToLower(q.Author)) {
+		return false
+	}
+	if q.Year != "" && !strings.HasPrefix(b.FinishedAt, q.Year) {
+		return false
+	}
+	if q.From != "" || q.To != "" {
+		if b.FinishedAt == "" {
+			return false
+		}
+		// Compare only as many characters as the bound has, so to=2024 includes all of 2024
+		if q.From != "" && prefix(b.FinishedAt, len(q.From)) < q.From {
+			return false
+		}
+		if q.To != "" && prefix(b.FinishedAt, len(q.To)) > q.To {
+			return false
+		}
+	}
+	return true
+}
+
+func prefix(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// sortBookItems orders the list. Without an explicit sort, search results stay
+// in relevance order and the shelf keeps its own order with lang books first.
+func sortBookItems(items []bookListItem, q bookListQuery) {
+	key := strings.TrimPrefix(q.Sort, "-")
+	desc := strings.HasPrefix(q.Sort, "-")
+
+	var less func(a, b *bookListItem) int
+	switch key {
+	case "finished_at":
+		less = func(a, b *bookListItem) int { return strings.Compare(a.FinishedAt, b.FinishedAt) }
+	case "started_at":
+		less = func(a, b *bookListItem) int { return strings.Compare(a.StartedAt, b.StartedAt) }
+	case "rating":
+		less = func(a, b *bookListItem) int { return a.Rating - b.Rating }
+	case "title":
+		col := collatorFor(q.Lang)
+		less = func(a, b *bookListItem) int { return col.CompareString(a.Title, b.Title) }
+	case "author":
+		col := collatorFor(q.Lang)
+		less = func(a, b *bookListItem) int { return col.CompareString(a.Author, b.Author) }
+	case "relevance":
+		less = func(a, b *bookListItem) int { return compareScore(*b.Score, *a.Score) }
+	default:
+		if q.Lang != "" && q.Q == "" {
+			// Sort by language priority: current language first
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Language == q.Lang && items[j].Language != q.Lang
+			})
+		}
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return less(&items[j], &items[i]) < 0
+		}
+		return less(&items[i], &items[j]) < 0
+	})
+}
+
+func compareScore(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// collatorFor returns a collator for the UI language (Japanese orders kana by gojūon)
+func collatorFor(lang string) *collate.Collator {
+	tag := language.English
+	if lang == "ja" {
+		tag = language.Japanese
+	}
+	return collate.New(tag, collate.IgnoreCase, collate.IgnoreWidth)
+}
+
+// setPaginationHeaders reports the total and links to neighbouring pages
+func setPaginationHeaders(c *gin.Context, q bookListQuery, total int) {
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	if q.Limit == 0 {
+		return
+	}
+
+	pageURL := func(offset int) string {
+		u := url.URL{Path: c.Request.URL.Path}
+		values := c.Request.URL.Query()
+		values.Set("offset", strconv.Itoa(offset))
+		values.Set("limit", strconv.Itoa(q.Limit))
+		u.RawQuery = values.Encode()
+		return u.String()
+	}
+
+	var links []string
+	if q.Offset+q.Limit < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(q.Offset+q.Limit)))
+	}
+	if q.Offset > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(max(q.Offset-q.Limit, 0))))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -37,10 +39,11 @@ type ChatRequest struct {
 }
 
 type ChatResponseDTO struct {
-	Response    string   `json:"response"`
-	Emotion     string   `json:"emotion"`
-	Suggestions []string `json:"suggestions"`
-	SessionID   string   `json:"sessionId"`
+	Response    string                 `json:"response"`
+	Emotion     string                 `json:"emotion"`
+	Suggestions []string               `json:"suggestions"`
+	Quotes      map[string]agent.Quote `json:"quotes,omitempty"`
+	SessionID   string                 `json:"sessionId"`
 }
 
 var (
@@ -48,25 +51,46 @@ var (
 	agentMu        sync.RWMutex
 )
 
+// PersonaPath optionally describes how the default shelf talks (added to the system prompt)
+const PersonaPath = "data/persona.md"
+
+// loadPersona reads a persona file; a missing file means no persona
+func loadPersona(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[WARN] Failed to read persona %s: %v", path, err)
+		}
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
 // InitBookshelfAgent initializes the ADK-based bookshelf agent
 func InitBookshelfAgent() error {
 	ctx := context.Background()
 
 	// Load portfolio data
-	p, err := portfolio.LoadPortfolio("data/portfolio.json")
+	p, err := portfolio.LoadPortfolio(PortfolioPath)
 	if err != nil {
 		log.Printf("Warning: Failed to load portfolio: %v", err)
 		p = nil
+	} else {
+		ownerMu.Lock()
+		loadedPortfolio = p
+		ownerInfo = newOwnerInfo(p)
+		ownerMu.Unlock()
 	}
 
-	// Get books
-	books := GetBooks()
+	// Get the configured book repository
+	repo := GetBookRepository()
+	noteSearcher := initSemanticIndex(ctx)
 
 	// Create agent
 	agentMu.Lock()
 	defer agentMu.Unlock()
 
-	bookshelfAgent, err = agent.NewBookshelfAgent(ctx, books, p)
+	bookshelfAgent, err = agent.NewBookshelfAgent(ctx, repo, noteSearcher, p, agent.Options{Persona: loadPersona(PersonaPath)})
 	if err != nil {
 		return err
 	}
@@ -109,9 +133,11 @@ func HandleChat(c *gin.Context) {
 		return
 	}
 
+	target := currentShelf(c)
+
 	// Validate bookId exists if provided
 	if req.BookID != nil && *req.BookID != "" {
-		if GetBookByID(*req.BookID) == nil {
+		if target.books.GetByID(*req.BookID) == nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "The specified book was not found",
 				"code":  "BOOK_NOT_FOUND",
@@ -120,10 +146,7 @@ func HandleChat(c *gin.Context) {
 		}
 	}
 
-	agentMu.RLock()
-	currentAgent := bookshelfAgent
-	agentMu.RUnlock()
-
+	currentAgent := target.agent
 	if currentAgent == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error": "AI service is not available",
@@ -204,6 +227,7 @@ func HandleChat(c *gin.Context) {
 		Response:    resp.Response,
 		Emotion:     resp.Emotion,
 		Suggestions: resp.Suggestions,
+		Quotes:      resp.Quotes,
 		SessionID:   sessionID,
 	})
 }
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"talking-bookshelf/backend/internal/covers"
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/shelf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCoversDir holds the default shelf's cover images (COVERS_DIR overrides).
+// Extra shelves use data/shelves/<slug>/covers.
+const DefaultCoversDir = "data/covers"
+
+// fallbackCoverCacheControl makes clients revalidate placeholders and redirects,
+// so a cover file added later shows up right away instead of after max-age
+const fallbackCoverCacheControl = "no-cache"
+
+// defaultCovers is the default shelf's cover store, created on first use so COVERS_DIR from .env.local applies
+var defaultCovers = sync.OnceValue(func() *covers.Store {
+	return covers.NewStore(cmp.Or(os.Getenv("COVERS_DIR"), DefaultCoversDir))
+})
+
+// coverURL returns the URL clients should load the book's cover from: the local
+// cover or placeholder route, unless the book only has an external cover URL
+func (v shelfView) coverURL(book *model.Book) string {
+	if _, ok := v.covers.Find(book.ID); ok || book.Cover == "" {
+		if v.slug == shelf.DefaultSlug {
+			return model.CoverPath(book.ID)
+		}
+		return "/api/shelves/" + v.slug + model.CoverPath(book.ID)
+	}
+	return book.Cover
+}
+
+// bookResponse is book.ToResponse with the cover resolved for this shelf
+func (v shelfView) bookResponse(book *model.Book) model.BookResponse {
+	resp := book.ToResponse()
+	resp.Cover = v.coverURL(book)
+	return resp
+}
+
+// HandleGetCover serves a book's cover (GET /covers/:id, ?w= for a thumbnail).
+// A local cover file wins; otherwise an external cover URL is redirected to,
+// and books without any cover get an SVG placeholder. Only local files keep the
+// route's Cache-Control; the other two are always revalidated.
+func HandleGetCover(c *gin.Context) {
+	view := currentShelf(c)
+	book := view.books.GetByID(c.Param("id"))
+	if book == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+
+	width := 0
+	if w := c.Query("w"); w != "" {
+		n, err := strconv.Atoi(w)
+		if err != nil || !slices.Contains(covers.Widths, n) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("w must be one of %v", covers.Widths),
+				"code":  "INVALID_WIDTH",
+			})
+			return
+		}
+		width = n
+	}
+
+	if path, ok := view.covers.Find(book.ID); ok {
+		if width > 0 {
+			thumb, err := view.covers.Thumbnail(book.ID, width)
+			if err != nil {
+				// Serve the full-size cover rather than nothing
+				log.Printf("[COVER] Thumbnail %s w=%d failed: %v", book.ID, width, err)
+			} else {
+				path = thumb
+			}
+		}
+		c.File(path)
+		return
+	}
+
+	if strings.HasPrefix(book.Cover, "http://") || strings.HasPrefix(book.Cover, "https://") {
+		c.Header("Cache-Control", fallbackCoverCacheControl)
+		c.Redirect(http.StatusFound, book.Cover)
+		return
+	}
+
+	svg := covers.Placeholder(book.ID, book.Title, book.Author)
+	sum := sha256.Sum256(svg)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fallbackCoverCacheControl)
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
+}
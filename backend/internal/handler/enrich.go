@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"talking-bookshelf/backend/internal/enrich"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MetadataCachePath caches ISBN lookups so each edition is fetched once
+	MetadataCachePath = "data/" + enrich.CacheFile
+	// enrichTimeout bounds one admin lookup
+	enrichTimeout = 15 * time.Second
+)
+
+var (
+	metadataOnce     sync.Once
+	metadataProvider enrich.MetadataProvider
+	metadataErr      error
+)
+
+// currentMetadataProvider creates the server's provider on first use
+func currentMetadataProvider() (enrich.MetadataProvider, error) {
+	metadataOnce.Do(func() {
+		metadataProvider, metadataErr = enrich.NewMetadataProvider(MetadataCachePath)
+	})
+	return metadataProvider, metadataErr
+}
+
+// EnrichRequest is the optional body for POST /api/admin/books/:id/enrich
+type EnrichRequest struct {
+	ISBN  string `json:"isbn"`  // replaces the book's ISBN before the lookup
+	Force bool   `json:"force"` // overwrite fields the owner already set
+}
+
+// HandleAdminEnrichBook fills in a book's title, authors, publish year, page count,
+// subjects and cover from its ISBN (POST /api/admin/books/:id/enrich).
+// Fields that are already set are kept unless force is true (?force=true also works).
+func HandleAdminEnrichBook(c *gin.Context) {
+	id := c.Param("id")
+
+	var req EnrichRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+				"code":  "INVALID_REQUEST",
+			})
+			return
+		}
+	}
+	if c.Query("force") == "true" {
+		req.Force = true
+	}
+
+	provider, err := currentMetadataProvider()
+	if err != nil {
+		log.Printf("[ENRICH] %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Metadata lookup is misconfigured",
+			"code":  "METADATA_UNAVAILABLE",
+		})
+		return
+	}
+	if provider == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Metadata lookup is disabled (METADATA_PROVIDER=none)",
+			"code":  "METADATA_DISABLED",
+		})
+		return
+	}
+
+	existing := GetBookByID(id)
+	if existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+	isbn := cmp.Or(req.ISBN, existing.ISBN)
+
+	// Look up before taking reloadMu so a slow provider does not block reloads
+	ctx, cancel := context.WithTimeout(c.Request.Context(), enrichTimeout)
+	defer cancel()
+
+	meta, err := enrich.Lookup(ctx, provider, isbn)
+	switch {
+	case errors.Is(err, enrich.ErrNoISBN), errors.Is(err, enrich.ErrInvalidISBN):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "INVALID_ISBN",
+		})
+		return
+	case errors.Is(err, enrich.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("%s has no record for ISBN %s", provider.Name(), isbn),
+			"code":  "METADATA_NOT_FOUND",
+		})
+		return
+	case err != nil:
+		log.Printf("[ENRICH] Lookup for %s failed: %v", id, err)
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error": "Metadata lookup failed",
+			"code":  "METADATA_UNAVAILABLE",
+		})
+		return
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	// The book may have changed (or gone) during the lookup
+	if existing = GetBookByID(id); existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+
+	book := *existing
+	book.ISBN = cmp.Or(req.ISBN, book.ISBN)
+	changes := enrich.Apply(&book, meta, req.Force)
+	if len(changes) > 0 || book.ISBN != existing.ISBN {
+		if !persistBook(c, book) {
+			return
+		}
+		log.Printf("[ADMIN] Enriched book %s from %s (%d fields)", book.ID, provider.Name(), len(changes))
+	}
+	if changes == nil {
+		changes = []string{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"book":     currentShelf(c).bookResponse(&book),
+		"changes":  changes,
+		"provider": provider.Name(),
+	})
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"talking-bookshelf/backend/internal/blurb"
+	"talking-bookshelf/backend/internal/feed"
+	"talking-bookshelf/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// BlurbsPath caches the validated AI summaries used as public blurbs
+	BlurbsPath = "data/" + blurb.CacheFile
+	// DefaultFeedLimit is the number of entries in /feed.atom and /feed.rss (FEED_LIMIT overrides)
+	DefaultFeedLimit = 20
+	// MaxFeedLimit caps the limit query parameter of the feeds
+	MaxFeedLimit = 100
+)
+
+var (
+	blurbCache   *blurb.Cache
+	blurbCacheMu sync.Mutex
+)
+
+// getBlurbCache returns the blurb cache, loading it on first use after a reload
+func getBlurbCache() *blurb.Cache {
+	blurbCacheMu.Lock()
+	defer blurbCacheMu.Unlock()
+	if blurbCache == nil {
+		cache, err := blurb.LoadCache(BlurbsPath)
+		if err != nil {
+			log.Printf("[WARN] Ignoring blurb cache: %v", err)
+			cache = &blurb.Cache{}
+		}
+		blurbCache = cache
+	}
+	return blurbCache
+}
+
+// invalidateBlurbCache makes the next getBlurbCache call re-read the file
+func invalidateBlurbCache() {
+	blurbCacheMu.Lock()
+	blurbCache = nil
+	blurbCacheMu.Unlock()
+}
+
+// publicBlurb is the excerpt or validated summary of book ("" if neither exists)
+func publicBlurb(book model.Book) string {
+	return blurb.Public(book, getBlurbCache())
+}
+
+// HandleAtomFeed serves recently finished books as Atom (GET /feed.atom)
+func HandleAtomFeed(c *gin.Context) {
+	writeRecentFeed(c, "/feed.atom", "application/atom+xml; charset=utf-8", feed.Atom)
+}
+
+// HandleRSSFeed serves recently finished books as RSS 2.0 (GET /feed.rss)
+func HandleRSSFeed(c *gin.Context) {
+	writeRecentFeed(c, "/feed.rss", "application/rss+xml; charset=utf-8", feed.RSS)
+}
+
+func writeRecentFeed(c *gin.Context, selfPath, contentType string, render func(feed.Channel) ([]byte, error)) {
+	limit := feedLimit()
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxFeedLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100", "code": "INVALID_QUERY"})
+			return
+		}
+		limit = n
+	}
+
+	base := publicBaseURL(c)
+	ch := recentBooksChannel(base, limit)
+	ch.Self = base + selfPath
+	if c.Request.URL.RawQuery != "" {
+		ch.Self += "?" + c.Request.URL.RawQuery
+	}
+
+	data, err := render(ch)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// recentBooksChannel lists finished books, most recent first.
+// Entries carry the public blurb only; private notes never leave the server.
+func recentBooksChannel(base string, limit int) feed.Channel {
+	type dated struct {
+		book     model.Book
+		finished time.Time
+	}
+	var books []dated
+	for _, book := range GetBooks() {
+		if book.ReadingStatus() != model.StatusFinished {
+			continue
+		}
+		finished, err := time.Parse("2006-01-02", book.FinishedAt)
+		if err != nil {
+			continue
+		}
+		books = append(books, dated{book: book, finished: finished})
+	}
+	sort.SliceStable(books, func(i, j int) bool { return books[i].finished.After(books[j].finished) })
+	if len(books) > limit {
+		books = books[:limit]
+	}
+
+	_, updated := DataVersion()
+
+	ch := feed.Channel{
+		ID:          base + "/",
+		Title:       siteTitle(pageOwner()),
+		Description: "Recently finished books",
+		Link:        base + "/",
+		Updated:     updated,
+	}
+	for _, d := range books {
+		ch.Items = append(ch.Items, feed.Item{
+			ID:        base + "/books/" + url.PathEscape(d.book.ID),
+			Title:     d.book.Title,
+			Link:      base + "/books/" + url.PathEscape(d.book.ID),
+			Authors:   d.book.Authors(),
+			Summary:   publicBlurb(d.book),
+			Published: d.finished,
+		})
+	}
+	return ch
+}
+
+// feedLimit reads FEED_LIMIT, falling back to DefaultFeedLimit
+func feedLimit() int {
+	if v := os.Getenv("FEED_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return min(n, MaxFeedLimit)
+		}
+		log.Printf("[WARN] Invalid FEED_LIMIT %q, using %d", v, DefaultFeedLimit)
+	}
+	return DefaultFeedLimit
+}
+
+// publicBaseURL is PUBLIC_BASE_URL when set, otherwise the scheme and host of the request
+// (X-Forwarded-Proto is honored for Cloud Run)
+func publicBaseURL(c *gin.Context) string {
+	if base := os.Getenv("PUBLIC_BASE_URL"); base != "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagCount is one entry of GET /api/tags
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// GenreCount is one entry of GET /api/genres
+type GenreCount struct {
+	model.Genre
+	Count int `json:"count"`
+}
+
+// CollectionSummary is one entry of GET /api/shelves
+type CollectionSummary struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// HandleGetTags lists the tags in use with their book counts, most used first (GET /api/tags).
+// lang selects the collation of tags with the same count.
+func HandleGetTags(c *gin.Context) {
+	labels := countLabels(currentShelf(c).books.GetAll(), c.Query("lang"), func(b *model.Book) []string { return b.Tags })
+	tags := make([]TagCount, 0, len(labels))
+	for _, l := range labels {
+		tags = append(tags, TagCount{Tag: l.name, Count: l.count})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Count > tags[j].Count })
+	c.JSON(http.StatusOK, tags)
+}
+
+// HandleGetGenres lists the controlled genres with their book counts (GET /api/genres)
+func HandleGetGenres(c *gin.Context) {
+	counts := make(map[string]int)
+	for _, book := range currentShelf(c).books.GetAll() {
+		for _, id := range book.Genres {
+			counts[id]++
+		}
+	}
+
+	genres := make([]GenreCount, 0, len(model.Genres))
+	for _, g := range model.Genres {
+		genres = append(genres, GenreCount{Genre: g, Count: counts[g.ID]})
+	}
+	c.JSON(http.StatusOK, genres)
+}
+
+// HandleGetCollections lists the owner's named shelves, stored as collections (GET /api/shelves).
+// Books are listed with GET /api/books?collection=<name>.
+func HandleGetCollections(c *gin.Context) {
+	labels := countLabels(currentShelf(c).books.GetAll(), c.Query("lang"), func(b *model.Book) []string { return b.Collections })
+	collections := make([]CollectionSummary, 0, len(labels))
+	for _, l := range labels {
+		collections = append(collections, CollectionSummary{Name: l.name, Count: l.count})
+	}
+	c.JSON(http.StatusOK, collections)
+}
+
+type labelCount struct {
+	name  string
+	count int
+}
+
+// countLabels counts free-form labels case-insensitively, keeping the first
+// spelling seen, and returns them sorted by name in the collation of lang
+func countLabels(books []model.Book, lang string, labels func(*model.Book) []string) []labelCount {
+	index := make(map[string]int)
+	var result []labelCount
+	for i := range books {
+		seen := make(map[string]bool)
+		for _, label := range labels(&books[i]) {
+			label = strings.TrimSpace(label)
+			key := strings.ToLower(label)
+			if label == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if j, ok := index[key]; ok {
+				result[j].count++
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, labelCount{name: label, count: 1})
+		}
+	}
+
+	col := collatorFor(lang)
+	sort.SliceStable(result, func(i, j int) bool { return col.CompareString(result[i].name, result[j].name) < 0 })
+	return result
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/opds"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OPDS catalog paths
+const (
+	opdsRoot       = "/opds"
+	opdsSearchDesc = "/opds/opensearch.xml"
+)
+
+// OPDSSearchPageSize is the number of results per page of GET /opds/search
+const OPDSSearchPageSize = 50
+
+// languageNames labels the language navigation entries
+var languageNames = map[string]string{"ja": "日本語", "en": "English"}
+
+// HandleOPDSRoot serves the start navigation feed (GET /opds)
+func HandleOPDSRoot(c *gin.Context) {
+	_, updated := DataVersion()
+	title := siteTitle(pageOwner())
+
+	feed := newOPDSFeed("urn:talking-bookshelf:root", title, opdsRoot, opds.NavigationType, updated)
+	feed.Entries = []opds.Entry{
+		opds.NavigationEntry("urn:talking-bookshelf:books", "All books", opdsRoot+"/books", opds.AcquisitionType, "Every book on the shelf, most recently finished first", updated),
+		opds.NavigationEntry("urn:talking-bookshelf:authors", "By author", opdsRoot+"/authors", opds.NavigationType, "", updated),
+		opds.NavigationEntry("urn:talking-bookshelf:years", "By year finished", opdsRoot+"/years", opds.NavigationType, "", updated),
+		opds.NavigationEntry("urn:talking-bookshelf:languages", "By language", opdsRoot+"/languages", opds.NavigationType, "", updated),
+	}
+	writeFeed(c, feed)
+}
+
+// HandleOPDSBooks serves every book as an acquisition feed (GET /opds/books)
+func HandleOPDSBooks(c *gin.Context) {
+	writeBookFeed(c, "urn:talking-bookshelf:books", "All books", opdsRoot+"/books", GetBooks())
+}
+
+// HandleOPDSAuthors lists authors (GET /opds/authors)
+func HandleOPDSAuthors(c *gin.Context) {
+	counts := make(map[string]int)
+	for _, book := range GetBooks() {
+		for _, name := range book.Authors() {
+			counts[name]++
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	col := collatorFor("ja")
+	sort.Slice(names, func(i, j int) bool { return col.CompareString(names[i], names[j]) < 0 })
+
+	entries := make([]opds.Entry, len(names))
+	for i, name := range names {
+		entries[i] = groupEntry("author", name, name, opdsRoot+"/authors/"+url.PathEscape(name), counts[name])
+	}
+	writeNavigationFeed(c, "urn:talking-bookshelf:authors", "By author", opdsRoot+"/authors", entries)
+}
+
+// HandleOPDSAuthor lists one author's books (GET /opds/authors/:name)
+func HandleOPDSAuthor(c *gin.Context) {
+	name := c.Param("name")
+	books := filterBooks(func(b *model.Book) bool {
+		for _, author := range b.Authors() {
+			if author == name {
+				return true
+			}
+		}
+		return false
+	})
+	if len(books) == 0 {
+		writeOPDSError(c, http.StatusNotFound, "AUTHOR_NOT_FOUND", "Author not found")
+		return
+	}
+	writeBookFeed(c, "urn:talking-bookshelf:author:"+url.PathEscape(name), name, opdsRoot+"/authors/"+url.PathEscape(name), books)
+}
+
+// HandleOPDSYears lists the years books were finished, newest first (GET /opds/years)
+func HandleOPDSYears(c *gin.Context) {
+	counts := make(map[string]int)
+	for _, book := range GetBooks() {
+		if len(book.FinishedAt) >= 4 {
+			counts[book.FinishedAt[:4]]++
+		}
+	}
+	years := make([]string, 0, len(counts))
+	for year := range counts {
+		years = append(years, year)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(years)))
+
+	entries := make([]opds.Entry, len(years))
+	for i, year := range years {
+		entries[i] = groupEntry("year", year, year, opdsRoot+"/years/"+year, counts[year])
+	}
+	writeNavigationFeed(c, "urn:talking-bookshelf:years", "By year finished", opdsRoot+"/years", entries)
+}
+
+// HandleOPDSYear lists the books finished in a year (GET /opds/years/:year)
+func HandleOPDSYear(c *gin.Context) {
+	year := c.Param("year")
+	books := filterBooks(func(b *model.Book) bool { return len(year) == 4 && strings.HasPrefix(b.FinishedAt, year) })
+	if len(books) == 0 {
+		writeOPDSError(c, http.StatusNotFound, "YEAR_NOT_FOUND", "No books finished in that year")
+		return
+	}
+	writeBookFeed(c, "urn:talking-bookshelf:year:"+year, year, opdsRoot+"/years/"+year, books)
+}
+
+// HandleOPDSLanguages lists the languages on the shelf (GET /opds/languages)
+func HandleOPDSLanguages(c *gin.Context) {
+	counts := make(map[string]int)
+	for _, book := range GetBooks() {
+		if book.Language != "" {
+			counts[book.Language]++
+		}
+	}
+	langs := make([]string, 0, len(counts))
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	entries := make([]opds.Entry, len(langs))
+	for i, lang := range langs {
+		entries[i] = groupEntry("language", lang, languageLabel(lang), opdsRoot+"/languages/"+url.PathEscape(lang), counts[lang])
+	}
+	writeNavigationFeed(c, "urn:talking-bookshelf:languages", "By language", opdsRoot+"/languages", entries)
+}
+
+// HandleOPDSLanguage lists the books in a language (GET /opds/languages/:lang)
+func HandleOPDSLanguage(c *gin.Context) {
+	lang := c.Param("lang")
+	books := filterBooks(func(b *model.Book) bool { return b.Language == lang })
+	if len(books) == 0 {
+		writeOPDSError(c, http.StatusNotFound, "LANGUAGE_NOT_FOUND", "No books in that language")
+		return
+	}
+	writeBookFeed(c, "urn:talking-bookshelf:language:"+url.PathEscape(lang), languageLabel(lang), opdsRoot+"/languages/"+url.PathEscape(lang), books)
+}
+
+// HandleOPDSSearch runs the shelf search and returns the hits in relevance order,
+// OPDSSearchPageSize at a time (GET /opds/search?q=&offset=)
+func HandleOPDSSearch(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		writeOPDSError(c, http.StatusBadRequest, "INVALID_QUERY", "q is required")
+		return
+	}
+	offset, err := intParam(c, "offset", 0)
+	if err != nil {
+		writeOPDSError(c, http.StatusBadRequest, "INVALID_QUERY", err.Error())
+		return
+	}
+
+	results := GetBookRepository().SearchAll(q)
+	page := results[min(offset, len(results)):min(offset+OPDSSearchPageSize, len(results))]
+	books := make([]model.Book, len(page))
+	for i, result := range page {
+		books[i] = result.Book
+	}
+
+	_, updated := DataVersion()
+	pageURL := func(offset int) string {
+		href := opdsRoot + "/search?q=" + url.QueryEscape(q)
+		if offset > 0 {
+			href += "&offset=" + strconv.Itoa(offset)
+		}
+		return href
+	}
+	feed := newOPDSFeed("urn:talking-bookshelf:search:"+url.QueryEscape(q), "Search: "+q, pageURL(offset), opds.AcquisitionType, updated)
+	feed.Links = append(feed.Links, opds.Link{Rel: opds.RelUp, Href: opdsRoot, Type: opds.NavigationType})
+	if offset+OPDSSearchPageSize < len(results) {
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelNext, Href: pageURL(offset + OPDSSearchPageSize), Type: opds.AcquisitionType})
+	}
+	if offset > 0 {
+		feed.Links = append(feed.Links, opds.Link{Rel: opds.RelPrevious, Href: pageURL(max(offset-OPDSSearchPageSize, 0)), Type: opds.AcquisitionType})
+	}
+	feed.TotalResults = len(results)
+	feed.StartIndex = offset + 1
+	feed.ItemsPerPage = OPDSSearchPageSize
+	feed.Entries = bookEntries(books, updated)
+	writeFeed(c, feed)
+}
+
+// HandleOPDSSearchDescription serves the OpenSearch description (GET /opds/opensearch.xml)
+func HandleOPDSSearchDescription(c *gin.Context) {
+	desc := opds.NewOpenSearchDescription("Bookshelf", "Search the books on this shelf by title, author or notes", opdsRoot+"/search?q={searchTerms}")
+	data, err := desc.Marshal()
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		c.String(http.StatusInternalServerError, "Failed to build catalog")
+		return
+	}
+	c.Data(http.StatusOK, opds.OpenSearchType+";charset=utf-8", data)
+}
+
+func newOPDSFeed(id, title, self, kind string, updated time.Time) *opds.Feed {
+	return opds.NewFeed(id, title, self, kind, opdsRoot, opdsSearchDesc, updated)
+}
+
+func writeNavigationFeed(c *gin.Context, id, title, self string, entries []opds.Entry) {
+	_, updated := DataVersion()
+	feed := newOPDSFeed(id, title, self, opds.NavigationType, updated)
+	feed.Links = append(feed.Links, opds.Link{Rel: opds.RelUp, Href: opdsRoot, Type: opds.NavigationType})
+	feed.Entries = entries
+	writeFeed(c, feed)
+}
+
+// writeBookFeed writes books as an acquisition feed, most recently finished first
+func writeBookFeed(c *gin.Context, id, title, self string, books []model.Book) {
+	sorted := make([]model.Book, len(books))
+	copy(sorted, books)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].FinishedAt > sorted[j].FinishedAt })
+
+	_, updated := DataVersion()
+	feed := newOPDSFeed(id, title, self, opds.AcquisitionType, updated)
+	feed.Links = append(feed.Links, opds.Link{Rel: opds.RelUp, Href: opdsRoot, Type: opds.NavigationType})
+	feed.Entries = bookEntries(sorted, updated)
+	writeFeed(c, feed)
+}
+
+func bookEntries(books []model.Book, updated time.Time) []opds.Entry {
+	entries := make([]opds.Entry, len(books))
+	for i, book := range books {
+		entries[i] = opds.BookEntry(book, "/api/books/"+url.PathEscape(book.ID), updated)
+	}
+	return entries
+}
+
+func groupEntry(kind, key, title, href string, count int) opds.Entry {
+	_, updated := DataVersion()
+	summary := "1 book"
+	if count != 1 {
+		summary = fmt.Sprintf("%d books", count)
+	}
+	return opds.NavigationEntry("urn:talking-bookshelf:"+kind+":"+url.PathEscape(key), title, href, opds.AcquisitionType, summary, updated)
+}
+
+func filterBooks(keep func(b *model.Book) bool) []model.Book {
+	var books []model.Book
+	for _, book := range GetBooks() {
+		if keep(&book) {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+func languageLabel(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// writeFeed sends the feed with its OPDS media type (the type of its self link)
+func writeFeed(c *gin.Context, feed *opds.Feed) {
+	writeFeedStatus(c, http.StatusOK, feed)
+}
+
+// writeOPDSError reports a failed catalog request as a feed with a single entry
+// holding the message, so that reading apps can show it instead of a parse error
+func writeOPDSError(c *gin.Context, status int, code, message string) {
+	now := time.Now()
+	feed := newOPDSFeed("urn:talking-bookshelf:error:"+code, message, c.Request.URL.RequestURI(), opds.NavigationType, now)
+	feed.Links = append(feed.Links, opds.Link{Rel: opds.RelUp, Href: opdsRoot, Type: opds.NavigationType})
+	feed.Entries = []opds.Entry{opds.NavigationEntry("urn:talking-bookshelf:error:"+code, message, opdsRoot, opds.NavigationType, code, now)}
+	writeFeedStatus(c, status, feed)
+}
+
+func writeFeedStatus(c *gin.Context, status int, feed *opds.Feed) {
+	data, err := feed.Marshal()
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		c.String(http.StatusInternalServerError, "Failed to build catalog")
+		return
+	}
+	c.Data(status, feed.Links[0].Type+";charset=utf-8", data)
+}
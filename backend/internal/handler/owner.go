@@ -20,34 +20,64 @@ type OwnerInfo struct {
 	Social  []SocialLink `json:"social,omitempty"`
 }
 
+// PortfolioPath is the portfolio data file
+const PortfolioPath = "data/portfolio.json"
+
 var (
 	ownerInfo *OwnerInfo
-	ownerOnce sync.Once
+	// loadedPortfolio is the portfolio the current agent was built with
+	loadedPortfolio *portfolio.Portfolio
+	ownerMu         sync.RWMutex
 )
 
-func loadOwner() {
-	ownerOnce.Do(func() {
-		p, err := portfolio.LoadPortfolio("data/portfolio.json")
-		if err != nil {
-			return
-		}
-		social := make([]SocialLink, 0, len(p.Social))
-		for _, s := range p.Social {
-			social = append(social, SocialLink{
-				Name: s.Name,
-				URL:  s.URL,
-			})
-		}
-		ownerInfo = &OwnerInfo{
-			Name:    p.About.Name,
-			Tagline: p.About.Tagline,
-			Social:  social,
-		}
-	})
+// currentPortfolio returns the portfolio currently being served (nil if none loaded)
+func currentPortfolio() *portfolio.Portfolio {
+	ownerMu.RLock()
+	defer ownerMu.RUnlock()
+	return loadedPortfolio
+}
+
+// newOwnerInfo builds the public owner info from the portfolio
+func newOwnerInfo(p *portfolio.Portfolio) *OwnerInfo {
+	social := make([]SocialLink, 0, len(p.Social))
+	for _, s := range p.Social {
+		social = append(social, SocialLink{
+			Name: s.Name,
+			URL:  s.URL,
+		})
+	}
+	return &OwnerInfo{
+		Name:    p.About.Name,
+		Tagline: p.About.Tagline,
+		Social:  social,
+	}
+}
+
+// getOwnerInfo returns the cached owner info, loading it on first use
+func getOwnerInfo() *OwnerInfo {
+	ownerMu.RLock()
+	info := ownerInfo
+	ownerMu.RUnlock()
+	if info != nil {
+		return info
+	}
+
+	p, err := portfolio.LoadPortfolio(PortfolioPath)
+	if err != nil {
+		return nil
+	}
+
+	ownerMu.Lock()
+	defer ownerMu.Unlock()
+	if ownerInfo == nil {
+		ownerInfo = newOwnerInfo(p)
+		loadedPortfolio = p
+	}
+	return ownerInfo
 }
 
 func HandleGetOwner(c *gin.Context) {
-	loadOwner()
+	ownerInfo := currentShelf(c).owner
 	if ownerInfo == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load owner info"})
 		return
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/pages"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	pageRenderer *pages.Renderer
+	// appShell is the unmodified index.html, served for unknown books
+	appShell []byte
+)
+
+// InitPages loads the built index.html as the shell of the server-rendered pages
+func InitPages(indexPath string) error {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", indexPath, err)
+	}
+	renderer, err := pages.NewRenderer(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", indexPath, err)
+	}
+	pageRenderer = renderer
+	appShell = data
+	return nil
+}
+
+// HandleHomePage renders the shelf (GET /)
+func HandleHomePage(c *gin.Context) {
+	base := publicBaseURL(c)
+	owner := pageOwner()
+
+	view := currentShelf(c)
+	books := GetBooks()
+	responses := make([]model.BookResponse, len(books))
+	bookURLs := make([]string, len(books))
+	for i, book := range books {
+		responses[i] = view.bookResponse(&book)
+		bookURLs[i] = bookPageURL(base, book.ID)
+	}
+
+	siteName := siteTitle(owner)
+	description := owner.Tagline
+	if description == "" {
+		description = fmt.Sprintf("%d books on %s", len(books), siteName)
+	}
+
+	jsonld := []any{pages.ItemListJSONLD(siteName, bookURLs)}
+	if p := currentPortfolio(); p != nil {
+		jsonld = append([]any{pages.PersonJSONLD(p.About, p.Social, base+"/")}, jsonld...)
+	}
+
+	page := pages.HomePage{
+		Meta: pages.Meta{
+			SiteName:    siteName,
+			Title:       siteName,
+			Description: description,
+			URL:         base + "/",
+			OGType:      "website",
+			JSONLD:      jsonld,
+		},
+		Owner: owner,
+		Books: responses,
+	}
+	renderPage(c, func(buf *bytes.Buffer) error { return pageRenderer.RenderHome(buf, page) })
+}
+
+// HandleBookPage renders one book (GET /books/:id); unknown IDs get the plain app with 404
+func HandleBookPage(c *gin.Context) {
+	book := GetBookByID(c.Param("id"))
+	if book == nil {
+		c.Data(http.StatusNotFound, "text/html; charset=utf-8", appShell)
+		return
+	}
+
+	view := currentShelf(c)
+	base := publicBaseURL(c)
+	owner := pageOwner()
+	pageURL := bookPageURL(base, book.ID)
+	image := absoluteURL(base, view.coverURL(book))
+	blurb := publicBlurb(*book)
+
+	description := blurb
+	if description == "" {
+		description = fmt.Sprintf("%s / %s", book.Title, book.Author)
+	}
+
+	jsonld := []any{pages.BookJSONLD(view.bookResponse(book), pageURL, image, blurb, base+"/")}
+	if p := currentPortfolio(); p != nil {
+		jsonld = append(jsonld, pages.PersonJSONLD(p.About, p.Social, base+"/"))
+	}
+
+	page := pages.BookPage{
+		Meta: pages.Meta{
+			Lang:        book.Language,
+			SiteName:    siteTitle(owner),
+			Title:       fmt.Sprintf("%s | %s", book.Title, siteTitle(owner)),
+			Description: description,
+			URL:         pageURL,
+			Image:       image,
+			OGType:      "book",
+			JSONLD:      jsonld,
+		},
+		Owner: owner,
+		Book:  view.bookResponse(book),
+		Blurb: blurb,
+		Image: image,
+	}
+	renderPage(c, func(buf *bytes.Buffer) error { return pageRenderer.RenderBook(buf, page) })
+}
+
+// HandleSitemap lists the home page and every book page (GET /sitemap.xml)
+func HandleSitemap(c *gin.Context) {
+	base := publicBaseURL(c)
+	_, modTime := DataVersion()
+	lastMod := ""
+	if !modTime.IsZero() {
+		lastMod = modTime.UTC().Format("2006-01-02")
+	}
+
+	urls := []pages.SitemapURL{{Loc: base + "/", LastMod: lastMod}}
+	for _, book := range GetBooks() {
+		urls = append(urls, pages.SitemapURL{Loc: bookPageURL(base, book.ID), LastMod: lastMod})
+	}
+
+	data, err := pages.Sitemap(urls)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build sitemap"})
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+// HandleRobots points crawlers at the sitemap (GET /robots.txt)
+func HandleRobots(c *gin.Context) {
+	c.String(http.StatusOK, "User-agent: *\nDisallow: /api/\nSitemap: %s/sitemap.xml\n", publicBaseURL(c))
+}
+
+func renderPage(c *gin.Context, render func(buf *bytes.Buffer) error) {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		log.Printf("[ERROR] %v", err)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", appShell)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func pageOwner() pages.Owner {
+	info := getOwnerInfo()
+	if info == nil {
+		return pages.Owner{}
+	}
+	return pages.Owner{Name: info.Name, Tagline: info.Tagline}
+}
+
+// siteTitle names the shelf in pages and feeds
+func siteTitle(owner pages.Owner) string {
+	if owner.Name == "" {
+		return "Bookshelf"
+	}
+	return owner.Name + "'s Bookshelf"
+}
+
+func bookPageURL(base, id string) string {
+	return base + "/books/" + url.PathEscape(id)
+}
+
+// absoluteURL resolves a site-relative path (e.g. a cover under /assets) against base
+func absoluteURL(base, ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref
+	}
+	return base + "/" + strings.TrimPrefix(ref, "/")
+}
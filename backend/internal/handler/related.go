@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/related"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxRelatedLimit caps the limit parameter of GET /api/books/:id/related
+const MaxRelatedLimit = related.MaxLimit
+
+// RelatedBook is an item of GET /api/books/:id/related
+type RelatedBook struct {
+	model.BookResponse
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"` // e.g. "both tagged distributed-systems"; never from private notes
+}
+
+// HandleGetRelatedBooks lists the books most similar to a book, best first
+// (GET /api/books/:id/related). Supports limit and language (ja / en).
+func HandleGetRelatedBooks(c *gin.Context) {
+	view := currentShelf(c)
+	id := c.Param("id")
+	if view.books.GetByID(id) == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+
+	limit, err := intParam(c, "limit", related.DefaultLimit)
+	if err == nil && (limit == 0 || limit > MaxRelatedLimit) {
+		err = fmt.Errorf("limit must be between 1 and %d", MaxRelatedLimit)
+	}
+	lang := c.Query("language")
+	if err == nil && lang != "" && lang != "ja" && lang != "en" {
+		err = fmt.Errorf("language must be ja or en")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "INVALID_QUERY"})
+		return
+	}
+
+	items := []RelatedBook{}
+	for _, m := range view.books.Related(id) {
+		if lang != "" && m.Book.Language != lang {
+			continue
+		}
+		items = append(items, RelatedBook{BookResponse: view.bookResponse(&m.Book), Score: m.Score, Reasons: m.Reasons})
+		if len(items) == limit {
+			break
+		}
+	}
+	c.JSON(http.StatusOK, items)
+}
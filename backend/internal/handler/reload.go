@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/portfolio"
+	"talking-bookshelf/backend/internal/shelf"
+	"talking-bookshelf/backend/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDataWatchInterval is how often data files are polled for changes
+const DefaultDataWatchInterval = 5 * time.Second
+
+// reloadMu serializes reloads (file watcher and admin endpoint)
+var reloadMu sync.Mutex
+
+// ReloadResult summarizes a successful reload
+type ReloadResult struct {
+	Books int    `json:"books"`
+	Owner string `json:"owner"`
+}
+
+// ReloadData re-reads books.json and portfolio.json (and the blurb cache), validates them and swaps
+// the book repository, the agent and the owner info in one step.
+// On any error nothing is swapped and the previous data keeps serving.
+// Chats already in flight hold a reference to the old agent and finish on it.
+func ReloadData() (*ReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	snapshot, err := loadBookSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := portfolio.LoadPortfolio(PortfolioPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PortfolioPath, err)
+	}
+
+	if err := swapData(snapshot, p); err != nil {
+		return nil, err
+	}
+	CheckBookData()
+
+	return &ReloadResult{
+		Books: len(snapshot.repo.GetAll()),
+		Owner: p.About.Name,
+	}, nil
+}
+
+// refreshBooks republishes the book store after an admin write, keeping the
+// current portfolio. Callers must hold reloadMu.
+func refreshBooks() error {
+	snapshot, err := loadBookSnapshot()
+	if err != nil {
+		return err
+	}
+
+	p := currentPortfolio()
+	if p == nil {
+		loaded, err := portfolio.LoadPortfolio(PortfolioPath)
+		if err != nil {
+			return err
+		}
+		p = loaded
+	}
+
+	return swapData(snapshot, p)
+}
+
+// bookSnapshot is a book repository ready to be published by swapData
+type bookSnapshot struct {
+	repo           deps.BookRepository
+	encryptedNotes bool
+}
+
+// loadBookSnapshot returns the repository to publish on reload.
+// With the sqlite store the database is the source of truth, so it is reused as is.
+func loadBookSnapshot() (bookSnapshot, error) {
+	if !usesJSONStore() {
+		return bookSnapshot{repo: GetBookRepository(), encryptedNotes: notesEncrypted.Load()}, nil
+	}
+
+	books, err := store.LoadBooksJSON(BooksJSONPath)
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	if err := store.ValidateBooks(books); err != nil {
+		return bookSnapshot{}, fmt.Errorf("invalid %s: %w", BooksJSONPath, err)
+	}
+	notes, err := store.NotesPolicyFromEnv()
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	encrypted, err := notes.Open(books, BooksJSONPath)
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	return bookSnapshot{repo: store.NewInMemoryBookRepository(books), encryptedNotes: encrypted}, nil
+}
+
+// swapData rebuilds the agent on top of the snapshot and p, then publishes the
+// repository, agent and owner info together. The semantic index is only updated
+// once the agent is built, so a failed swap leaves everything as it was.
+// Callers must hold reloadMu.
+func swapData(snapshot bookSnapshot, p *portfolio.Portfolio) error {
+	repo := snapshot.repo
+	agentMu.RLock()
+	currentAgent := bookshelfAgent
+	agentMu.RUnlock()
+
+	// Agent construction happens outside the locks; it shares sessions with the old agent
+	newAgent := currentAgent
+	if currentAgent != nil {
+		rebuilt, err := currentAgent.WithData(repo, p)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild agent: %w", err)
+		}
+		newAgent = rebuilt
+	}
+
+	notesEncrypted.Store(snapshot.encryptedNotes)
+	updateSemanticIndex(repo.GetAll())
+
+	agentMu.Lock()
+	bookRepoMu.Lock()
+	ownerMu.Lock()
+	bookshelfAgent = newAgent
+	bookRepo = repo
+	ownerInfo = newOwnerInfo(p)
+	loadedPortfolio = p
+	ownerMu.Unlock()
+	bookRepoMu.Unlock()
+	agentMu.Unlock()
+
+	invalidateBlurbCache()
+	invalidateDataVersion()
+	return nil
+}
+
+// usesJSONStore reports whether books are served from books.json
+func usesJSONStore() bool {
+	bookRepoMu.RLock()
+	defer bookRepoMu.RUnlock()
+	return bookStoreType == "json"
+}
+
+// HandleReload reloads the data files on demand (POST /api/admin/reload)
+func HandleReload(c *gin.Context) {
+	result, err := ReloadData()
+	if err != nil {
+		log.Printf("[RELOAD] Rejected: %v", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"code":  "RELOAD_FAILED",
+		})
+		return
+	}
+
+	log.Printf("[RELOAD] Data reloaded via admin endpoint: books=%d", result.Books)
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleReloadShelf reloads one additional shelf on demand (POST /api/admin/shelves/:slug/reload)
+func HandleReloadShelf(c *gin.Context) {
+	slug := c.Param("slug")
+	result, err := ReloadShelf(slug)
+	if err != nil {
+		log.Printf("[RELOAD] Rejected shelf %s: %v", slug, err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"code":  "RELOAD_FAILED",
+		})
+		return
+	}
+
+	log.Printf("[RELOAD] Shelf %s reloaded via admin endpoint: books=%d", slug, result.Books)
+	c.JSON(http.StatusOK, result)
+}
+
+// WatchDataFiles polls the data files, including those of every shelf under
+// ShelvesDir, and reloads what changed. A new shelf directory is loaded once
+// its files appear. Runs until ctx is cancelled.
+func WatchDataFiles(ctx context.Context, interval time.Duration) {
+	paths := []string{PortfolioPath, BlurbsPath}
+	if usesJSONStore() {
+		paths = append(paths, BooksJSONPath)
+	}
+
+	last := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		last[path] = statFile(path)
+	}
+	for _, files := range shelfFiles() {
+		for _, path := range files {
+			last[path] = statFile(path)
+		}
+	}
+
+	log.Printf("[RELOAD] Watching %v and %s every %v", paths, ShelvesDir, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if changedFiles(paths, last) {
+			result, err := ReloadData()
+			if err != nil {
+				log.Printf("[RELOAD] Rejected changed data, keeping previous data: %v", err)
+			} else {
+				log.Printf("[RELOAD] Data reloaded: books=%d owner=%s", result.Books, result.Owner)
+			}
+		}
+
+		for slug, files := range shelfFiles() {
+			if !changedFiles(files, last) {
+				continue
+			}
+			result, err := ReloadShelf(slug)
+			if err != nil {
+				log.Printf("[RELOAD] Rejected changed shelf %s, keeping previous data: %v", slug, err)
+				continue
+			}
+			log.Printf("[RELOAD] Shelf %s reloaded: books=%d owner=%s", slug, result.Books, result.Owner)
+		}
+	}
+}
+
+// changedFiles reports whether any of paths changed since last, updating last
+func changedFiles(paths []string, last map[string]fileStamp) bool {
+	changed := false
+	for _, path := range paths {
+		stamp := statFile(path)
+		if stamp != last[path] {
+			log.Printf("[RELOAD] Detected change in %s", path)
+			last[path] = stamp
+			changed = true
+		}
+	}
+	return changed
+}
+
+// shelfFiles lists the data files of every shelf directory under ShelvesDir by slug
+func shelfFiles() map[string][]string {
+	entries, err := os.ReadDir(ShelvesDir)
+	if err != nil {
+		return nil
+	}
+	files := make(map[string][]string)
+	for _, entry := range entries {
+		slug := entry.Name()
+		if !entry.IsDir() || !shelf.ValidSlug(slug) || slug == shelf.DefaultSlug {
+			continue
+		}
+		dir := filepath.Join(ShelvesDir, slug)
+		files[slug] = []string{
+			filepath.Join(dir, shelf.BooksFile),
+			filepath.Join(dir, shelf.PortfolioFile),
+			filepath.Join(dir, shelf.PersonaFile),
+		}
+	}
+	return files
+}
+
+// fileStamp identifies a version of a file on disk
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"log"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/semantic"
+)
+
+// BooksVectorsPath is the default shelf's embedding cache
+const BooksVectorsPath = "data/" + semantic.CacheFile
+
+// semanticIndex backs the semantic_search_books tool; nil when EMBEDDER=none.
+// Only touched at startup and under reloadMu.
+var semanticIndex *semantic.Index
+
+// initSemanticIndex builds the notes index for the current shelf.
+// Returns nil (tool disabled) when no embedder is configured or indexing fails.
+func initSemanticIndex(ctx context.Context) deps.NoteSearcher {
+	embedder, err := semantic.NewEmbedder(ctx)
+	if err != nil {
+		log.Printf("[WARN] Semantic search disabled: %v", err)
+		return nil
+	}
+	if embedder == nil {
+		log.Println("[INFO] Semantic search disabled (EMBEDDER=none)")
+		return nil
+	}
+
+	index := semantic.NewIndex(embedder, BooksVectorsPath)
+	index.SetEncryptedNotes(notesEncrypted.Load())
+	if err := index.Update(ctx, GetBooks()); err != nil {
+		log.Printf("[WARN] Semantic search disabled: %v", err)
+		return nil
+	}
+	semanticIndex = index
+	return index
+}
+
+// updateSemanticIndex re-embeds books whose notes changed. On failure the
+// previous vectors keep serving. Callers must hold reloadMu.
+func updateSemanticIndex(books []model.Book) {
+	if semanticIndex == nil {
+		return
+	}
+	semanticIndex.SetEncryptedNotes(notesEncrypted.Load())
+	if err := semanticIndex.Update(context.Background(), books); err != nil {
+		log.Printf("[SEMANTIC] Update failed, keeping previous vectors: %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"talking-bookshelf/backend/internal/agent"
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/covers"
+	"talking-bookshelf/backend/internal/semantic"
+	"talking-bookshelf/backend/internal/shelf"
+	"talking-bookshelf/backend/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShelvesDir holds the additional shelves, one directory per slug
+const ShelvesDir = "data/shelves"
+
+// shelfContextKey stores the *loadedShelf of a /api/shelves/:slug request
+const shelfContextKey = "shelf"
+
+// loadedShelf is an additional shelf with its own agent (nil if the agent could not be created)
+type loadedShelf struct {
+	shelf  *shelf.Shelf
+	owner  *OwnerInfo
+	agent  *agent.BookshelfAgent
+	covers *covers.Store
+	// version is the shelf's own data version for conditional GETs
+	version *dataVersion
+	// index is the shelf's semantic index, kept so reloads only re-embed changed notes (nil: disabled)
+	index *semantic.Index
+}
+
+var (
+	// extraShelves are the shelves under ShelvesDir by slug; the default shelf is not included
+	extraShelves   map[string]*loadedShelf
+	extraShelvesMu sync.RWMutex
+)
+
+// shelfEmbedder is shared by the additional shelves' semantic indexes, created on
+// first use so a shelf added while running gets one too (nil: disabled)
+var shelfEmbedder = sync.OnceValue(func() deps.Embedder {
+	embedder, err := semantic.NewEmbedder(context.Background())
+	if err != nil {
+		log.Printf("[SHELF] Semantic search disabled for shelves: %v", err)
+		return nil
+	}
+	return embedder
+})
+
+// shelfView is what the catalog and chat handlers need from the shelf a request targets
+type shelfView struct {
+	slug   string
+	books  deps.BookRepository
+	owner  *OwnerInfo
+	agent  *agent.BookshelfAgent
+	covers *covers.Store
+}
+
+// ShelfSummary describes a shelf in GET /api/tenants
+type ShelfSummary struct {
+	Slug  string `json:"slug"`
+	Owner string `json:"owner"`
+	Books int    `json:"books"`
+}
+
+// InitShelves loads the shelves under ShelvesDir and creates an agent for each.
+// Each agent has its own ADK app name, so sessions never cross shelves.
+// Must be called after InitBookshelfAgent.
+func InitShelves() {
+	ctx := context.Background()
+	notes, err := store.NotesPolicyFromEnv()
+	if err != nil {
+		log.Printf("[SHELF] Skipping %s: %v", ShelvesDir, err)
+		return
+	}
+	shelves, failed := shelf.LoadAll(ShelvesDir, notes)
+	for dir, err := range failed {
+		log.Printf("[SHELF] Skipping %s: %v", dir, err)
+	}
+	if len(shelves) == 0 {
+		return
+	}
+
+	loaded := make(map[string]*loadedShelf, len(shelves))
+	for _, s := range shelves {
+		ls, err := newLoadedShelf(ctx, s, nil)
+		if err != nil {
+			log.Printf("[SHELF] Skipping %s: %v", s.Slug, err)
+			continue
+		}
+		loaded[s.Slug] = ls
+		log.Printf("[SHELF] Loaded %s: books=%d owner=%s", s.Slug, len(s.Books.GetAll()), s.Portfolio.About.Name)
+	}
+
+	extraShelvesMu.Lock()
+	extraShelves = loaded
+	extraShelvesMu.Unlock()
+}
+
+// ReloadShelf re-reads the shelf in ShelvesDir/<slug> and swaps it in, adding it
+// if it is new. On any error the previous data keeps serving, as with ReloadData.
+func ReloadShelf(slug string) (*ReloadResult, error) {
+	if !shelf.ValidSlug(slug) || slug == shelf.DefaultSlug {
+		return nil, fmt.Errorf("invalid shelf slug %q", slug)
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	notes, err := store.NotesPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s, err := shelf.Load(filepath.Join(ShelvesDir, slug), notes)
+	if err != nil {
+		return nil, err
+	}
+
+	extraShelvesMu.RLock()
+	previous := extraShelves[slug]
+	extraShelvesMu.RUnlock()
+
+	ls, err := newLoadedShelf(context.Background(), s, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	extraShelvesMu.Lock()
+	if extraShelves == nil {
+		extraShelves = make(map[string]*loadedShelf)
+	}
+	extraShelves[slug] = ls
+	extraShelvesMu.Unlock()
+
+	return &ReloadResult{Books: len(s.Books.GetAll()), Owner: s.Portfolio.About.Name}, nil
+}
+
+// newLoadedShelf builds the agent and semantic index of s. When reloading, the
+// previous agent's sessions and the previous vectors are carried over.
+func newLoadedShelf(ctx context.Context, s *shelf.Shelf, previous *loadedShelf) (*loadedShelf, error) {
+	var index *semantic.Index
+	if previous != nil {
+		index = previous.index
+	} else if embedder := shelfEmbedder(); embedder != nil {
+		index = semantic.NewIndex(embedder, filepath.Join(s.Dir, semantic.CacheFile))
+		index.SetEncryptedNotes(s.EncryptedNotes)
+		if err := index.Update(ctx, s.Books.GetAll()); err != nil {
+			log.Printf("[SHELF] Semantic search disabled for %s: %v", s.Slug, err)
+			index = nil
+		}
+	}
+
+	var shelfAgent *agent.BookshelfAgent
+	var err error
+	if previous != nil && previous.agent != nil && previous.shelf.Persona == s.Persona {
+		// Same persona: keep the session service so chats in progress continue
+		if shelfAgent, err = previous.agent.WithData(s.Books, s.Portfolio); err != nil {
+			return nil, fmt.Errorf("failed to rebuild agent: %w", err)
+		}
+	} else {
+		var noteSearcher deps.NoteSearcher
+		if index != nil {
+			noteSearcher = index
+		}
+		shelfAgent, err = agent.NewBookshelfAgent(ctx, s.Books, noteSearcher, s.Portfolio, agent.Options{
+			AppName: agent.DefaultAppName + "_" + s.Slug,
+			Persona: s.Persona,
+		})
+		if err != nil {
+			log.Printf("[SHELF] Chat unavailable for %s: %v", s.Slug, err)
+		}
+	}
+
+	// A reloaded index is only touched once the agent is built, so a failed reload leaves it as it was
+	if previous != nil && index != nil {
+		index.SetEncryptedNotes(s.EncryptedNotes)
+		if err := index.Update(ctx, s.Books.GetAll()); err != nil {
+			log.Printf("[SHELF] Semantic search for %s keeps previous vectors: %v", s.Slug, err)
+		}
+	}
+
+	owner := newOwnerInfo(s.Portfolio)
+	return &loadedShelf{
+		shelf:   s,
+		owner:   owner,
+		agent:   shelfAgent,
+		covers:  covers.NewStore(filepath.Join(s.Dir, "covers")),
+		version: computeShelfVersion(s, owner),
+		index:   index,
+	}, nil
+}
+
+// RequireShelf resolves :slug for the /api/shelves/:slug routes.
+// "default" is the default shelf, the same data as the unprefixed routes.
+func RequireShelf(c *gin.Context) {
+	slug := c.Param("slug")
+	if slug == shelf.DefaultSlug {
+		c.Next()
+		return
+	}
+
+	extraShelvesMu.RLock()
+	s := extraShelves[slug]
+	extraShelvesMu.RUnlock()
+	if s == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Shelf not found",
+			"code":  "SHELF_NOT_FOUND",
+		})
+		return
+	}
+	c.Set(shelfContextKey, s)
+	c.Next()
+}
+
+// currentShelf returns the shelf the request targets (the default shelf outside /api/shelves/:slug)
+func currentShelf(c *gin.Context) shelfView {
+	if v, ok := c.Get(shelfContextKey); ok {
+		s := v.(*loadedShelf)
+		return shelfView{slug: s.shelf.Slug, books: s.shelf.Books, owner: s.owner, agent: s.agent, covers: s.covers}
+	}
+
+	agentMu.RLock()
+	defaultAgent := bookshelfAgent
+	agentMu.RUnlock()
+	return shelfView{slug: shelf.DefaultSlug, books: GetBookRepository(), owner: getOwnerInfo(), agent: defaultAgent, covers: defaultCovers()}
+}
+
+// HandleListShelves lists the default shelf and the additional ones (GET /api/tenants).
+// GET /api/shelves is the owner's named shelves (collections), as in the single-shelf API.
+func HandleListShelves(c *gin.Context) {
+	summaries := []ShelfSummary{{Slug: shelf.DefaultSlug, Books: len(GetBooks())}}
+	if owner := getOwnerInfo(); owner != nil {
+		summaries[0].Owner = owner.Name
+	}
+
+	extraShelvesMu.RLock()
+	for _, s := range extraShelves {
+		summaries = append(summaries, ShelfSummary{Slug: s.shelf.Slug, Owner: s.owner.Name, Books: len(s.shelf.Books.GetAll())})
+	}
+	extraShelvesMu.RUnlock()
+
+	// Default first, then by slug
+	extra := summaries[1:]
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Slug < extra[j].Slug })
+	c.JSON(http.StatusOK, summaries)
+}
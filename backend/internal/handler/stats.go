@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"talking-bookshelf/backend/internal/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetStats returns the shelf's reading statistics (GET /api/stats),
+// the same figures the agent gets from get_reading_stats
+func HandleGetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, stats.Compute(currentShelf(c).books.GetAll()))
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"talking-bookshelf/backend/internal/covers"
+	"talking-bookshelf/backend/internal/shelf"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dataVersion identifies the data currently being served
+type dataVersion struct {
+	hash    string    // content hash of the books, owner info and blurbs
+	modTime time.Time // newest mtime of the data files
+}
+
+var (
+	currentVersion   *dataVersion
+	currentVersionMu sync.Mutex
+)
+
+// DataVersion returns the content hash of the loaded books, owner info and blurbs and
+// the modification time of the data files. It changes whenever data is reloaded
+// or written through the admin API; conditional GETs are validated against it.
+func DataVersion() (string, time.Time) {
+	currentVersionMu.Lock()
+	defer currentVersionMu.Unlock()
+	if currentVersion == nil {
+		currentVersion = computeDataVersion()
+	}
+	return currentVersion.withCovers(defaultCovers())
+}
+
+// withCovers folds the covers directory into the version: which books have a
+// local cover changes their cover URL, and the directory can change at any time
+func (v *dataVersion) withCovers(store *covers.Store) (string, time.Time) {
+	coversHash, coversModTime := store.Version()
+	if coversHash == "" {
+		return v.hash, v.modTime
+	}
+	modTime := v.modTime
+	if coversModTime.After(modTime) {
+		modTime = coversModTime
+	}
+	sum := sha256.Sum256([]byte(v.hash + coversHash))
+	return hex.EncodeToString(sum[:]), modTime
+}
+
+// invalidateDataVersion makes the next DataVersion call rehash the data
+func invalidateDataVersion() {
+	currentVersionMu.Lock()
+	currentVersion = nil
+	currentVersionMu.Unlock()
+}
+
+func computeDataVersion() *dataVersion {
+	return newDataVersion(
+		map[string]any{"books": GetBooks(), "owner": getOwnerInfo(), "blurbs": getBlurbCache()},
+		[]string{bookDataPath(), PortfolioPath, BlurbsPath},
+	)
+}
+
+// ShelfDataVersion is DataVersion for the shelf a /api/shelves/:slug request targets,
+// so editing one shelf's files never leaves clients of another with stale 304s
+func ShelfDataVersion(c *gin.Context) (string, time.Time) {
+	if v, ok := c.Get(shelfContextKey); ok {
+		s := v.(*loadedShelf)
+		return s.version.withCovers(s.covers)
+	}
+	return DataVersion()
+}
+
+// computeShelfVersion hashes an additional shelf's books and owner info and takes its files' mtimes
+func computeShelfVersion(s *shelf.Shelf, owner *OwnerInfo) *dataVersion {
+	return newDataVersion(
+		map[string]any{"books": s.Books.GetAll(), "owner": owner, "persona": s.Persona},
+		[]string{
+			filepath.Join(s.Dir, shelf.BooksFile),
+			filepath.Join(s.Dir, shelf.PortfolioFile),
+			filepath.Join(s.Dir, shelf.PersonaFile),
+		},
+	)
+}
+
+// newDataVersion hashes the data (in key order) and takes the newest mtime of the files
+func newDataVersion(data map[string]any, paths []string) *dataVersion {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		if err := enc.Encode(data[key]); err != nil {
+			log.Printf("[WARN] Failed to hash %s: %v", key, err)
+		}
+	}
+
+	var modTime time.Time
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return &dataVersion{hash: hex.EncodeToString(h.Sum(nil)), modTime: modTime}
+}
+
+// bookDataPath returns the file backing the book store
+func bookDataPath() string {
+	if usesJSONStore() {
+		return BooksJSONPath
+	}
+	if dbPath := os.Getenv("BOOKS_DB_PATH"); dbPath != "" {
+		return dbPath
+	}
+	return DefaultBooksDBPath
+}
@@ -0,0 +1,146 @@
+// Package importer converts reading history exported from other services into model.Book entries.
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// Goodreads export column names used by the importer
+const (
+	grTitle             = "Title"
+	grAuthor            = "Author"
+	grAdditionalAuthors = "Additional Authors"
+	grISBN              = "ISBN"
+	grISBN13            = "ISBN13"
+	grDateRead          = "Date Read"
+	grMyReview          = "My Review"
+	grBookshelves       = "Bookshelves"
+	grExclusiveShelf    = "Exclusive Shelf"
+)
+
+// exclusiveShelves are Goodreads' built-in shelves; they are not worth keeping as notes
+var exclusiveShelves = map[string]bool{
+	"read":              true,
+	"to-read":           true,
+	"currently-reading": true,
+}
+
+var reviewBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// GoodreadsResult holds the parsed books and the rows that were skipped
+type GoodreadsResult struct {
+	Books   []model.Book
+	Skipped []string
+}
+
+// ParseGoodreadsCSV reads a Goodreads library export.
+// Only books on the "read" shelf are imported since the shelf holds finished books.
+// IDs and links are left empty; Merge assigns them.
+func ParseGoodreadsCSV(r io.Reader) (*GoodreadsResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, required := range []string{grTitle, grAuthor} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("not a Goodreads export: missing %q column", required)
+		}
+	}
+
+	result := &GoodreadsResult{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		field := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		title := field(grTitle)
+		if title == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("line %d: no title", line))
+			continue
+		}
+		if shelf := field(grExclusiveShelf); shelf != "" && shelf != "read" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("line %d: %s is on %q", line, title, shelf))
+			continue
+		}
+
+		author := field(grAuthor)
+		if extra := field(grAdditionalAuthors); extra != "" {
+			author += ", " + extra
+		}
+
+		isbn := NormalizeISBN(field(grISBN13))
+		if isbn == "" {
+			isbn = NormalizeISBN(field(grISBN))
+		}
+
+		result.Books = append(result.Books, model.Book{
+			Title:        title,
+			Author:       author,
+			ISBN:         isbn,
+			FinishedAt:   strings.ReplaceAll(field(grDateRead), "/", "-"),
+			PrivateNotes: goodreadsNotes(title, field(grMyReview), field(grBookshelves)),
+			Language:     DetectLanguage(title + " " + author),
+		})
+	}
+
+	return result, nil
+}
+
+// goodreadsNotes builds private notes in the same "title\nbody" shape as the hand-written ones
+func goodreadsNotes(title, review, bookshelves string) string {
+	review = strings.TrimSpace(reviewBreakRegex.ReplaceAllString(review, "\n"))
+
+	var shelves []string
+	for _, shelf := range strings.Split(bookshelves, ",") {
+		shelf = strings.TrimSpace(shelf)
+		if shelf != "" && !exclusiveShelves[shelf] {
+			shelves = append(shelves, shelf)
+		}
+	}
+
+	if review == "" && len(shelves) == 0 {
+		return ""
+	}
+
+	notes := title
+	if review != "" {
+		notes += "\n" + review
+	}
+	if len(shelves) > 0 {
+		notes += "\nShelves: " + strings.Join(shelves, ", ")
+	}
+	return notes
+}
+
+// NormalizeISBN strips the ="..." wrapper Goodreads uses, hyphens and spaces
+func NormalizeISBN(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "=")
+	s = strings.Trim(s, `"`)
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+	return strings.ToUpper(s)
+}
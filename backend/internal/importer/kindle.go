@@ -0,0 +1,369 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// ClippingKind is the type of a Kindle clipping
+type ClippingKind string
+
+const (
+	ClippingHighlight ClippingKind = "highlight"
+	ClippingNote      ClippingKind = "note"
+	ClippingBookmark  ClippingKind = "bookmark"
+)
+
+// Clipping is one entry of a Kindle "My Clippings.txt" file
+type Clipping struct {
+	Title    string
+	Author   string
+	Kind     ClippingKind
+	Location string
+	Page     string
+	AddedAt  string // YYYY-MM-DD
+	Text     string
+}
+
+const clippingSeparator = "=========="
+
+var (
+	// "Title (Author)" - the author is the last parenthesized group
+	clippingTitleRegex = regexp.MustCompile(`^(.*?)\s*\(([^()]*)\)\s*$`)
+
+	clippingLocationRegex = regexp.MustCompile(`(?i)(?:Location|Loc\.|位置No\.)\s*([\d]+(?:-[\d]+)?)`)
+	clippingPageRegex     = regexp.MustCompile(`(?i)(?:\bpage\s*([\dIVXLCDM]+(?:-[\dIVXLCDM]+)?)|([\d]+)\s*ページ|ページ\s*([\d]+))`)
+
+	// "作成日: 2024年3月15日金曜日 22:00:00"
+	clippingJaDateRegex = regexp.MustCompile(`(\d{4})年(\d{1,2})月(\d{1,2})日`)
+	// "Added on Friday, March 15, 2024 10:00:00 PM" / "Added on Friday, 15 March 2024 22:00:00"
+	clippingEnDateRegex = regexp.MustCompile(`(?i)Added on\s+(?:[A-Za-z]+,\s*)?([A-Za-z]+ \d{1,2}, \d{4}|\d{1,2} [A-Za-z]+ \d{4})`)
+)
+
+// ParseKindleClippings parses a Kindle "My Clippings.txt" file.
+// Both the English and Japanese Kindle formats are supported.
+// Entries whose metadata line can't be understood are returned in skipped.
+func ParseKindleClippings(r io.Reader) (clippings []Clipping, skipped []string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var entry []string
+	entryNum := 0
+	flush := func() {
+		if len(entry) == 0 {
+			return
+		}
+		entryNum++
+		clipping, reason := parseClipping(entry)
+		if reason != "" {
+			skipped = append(skipped, fmt.Sprintf("entry %d: %s", entryNum, reason))
+		} else {
+			clippings = append(clippings, clipping)
+		}
+		entry = entry[:0]
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\ufeff")
+		if strings.TrimSpace(line) == clippingSeparator {
+			flush()
+			continue
+		}
+		entry = append(entry, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read clippings: %w", err)
+	}
+	flush()
+
+	return clippings, skipped, nil
+}
+
+// parseClipping parses the lines between two separators:
+// the title line, the metadata line, a blank line, then the text
+func parseClipping(lines []string) (Clipping, string) {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return Clipping{}, "incomplete entry"
+	}
+
+	var c Clipping
+	titleLine := strings.TrimSpace(lines[0])
+	if m := clippingTitleRegex.FindStringSubmatch(titleLine); m != nil && m[1] != "" {
+		c.Title, c.Author = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	} else {
+		c.Title = titleLine
+	}
+
+	meta := strings.TrimSpace(lines[1])
+	switch {
+	case strings.Contains(meta, "Highlight") || strings.Contains(meta, "ハイライト"):
+		c.Kind = ClippingHighlight
+	case strings.Contains(meta, "Note") || strings.Contains(meta, "メモ"):
+		c.Kind = ClippingNote
+	case strings.Contains(meta, "Bookmark") || strings.Contains(meta, "ブックマーク"):
+		c.Kind = ClippingBookmark
+	default:
+		return Clipping{}, fmt.Sprintf("unknown clipping type in %q", meta)
+	}
+
+	if m := clippingLocationRegex.FindStringSubmatch(meta); m != nil {
+		c.Location = m[1]
+	}
+	if m := clippingPageRegex.FindStringSubmatch(meta); m != nil {
+		c.Page = firstNonEmpty(m[1:]...)
+	}
+	c.AddedAt = parseClippingDate(meta)
+
+	c.Text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if c.Kind != ClippingBookmark && c.Text == "" {
+		return Clipping{}, fmt.Sprintf("empty %s in %s", c.Kind, c.Title)
+	}
+	return c, ""
+}
+
+func parseClippingDate(meta string) string {
+	if m := clippingJaDateRegex.FindStringSubmatch(meta); m != nil {
+		if t, err := time.Parse("2006-1-2", m[1]+"-"+m[2]+"-"+m[3]); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	if m := clippingEnDateRegex.FindStringSubmatch(meta); m != nil {
+		for _, layout := range []string{"January 2, 2006", "2 January 2006"} {
+			if t, err := time.Parse(layout, m[1]); err == nil {
+				return t.Format("2006-01-02")
+			}
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ClippingGroup is the highlights collected for one Kindle title
+type ClippingGroup struct {
+	Title      string
+	Author     string
+	Highlights []model.Highlight
+	Bookmarks  int
+}
+
+// GroupClippings groups clippings by book and turns them into highlights.
+// A note is attached to the highlight ending at the same location (that is how
+// Kindle records a note typed on a selection); otherwise it becomes an entry with
+// only a Note, since it is the owner's words rather than a passage of the book.
+// Kindle keeps the old clipping when a highlight is extended, so highlights
+// contained in a longer one from the same book are dropped.
+func GroupClippings(clippings []Clipping) []ClippingGroup {
+	var groups []ClippingGroup
+	index := make(map[string]int)
+
+	for _, c := range clippings {
+		key := c.Title + "\x00" + c.Author
+		i, ok := index[key]
+		if !ok {
+			groups = append(groups, ClippingGroup{Title: c.Title, Author: c.Author})
+			i = len(groups) - 1
+			index[key] = i
+		}
+		g := &groups[i]
+
+		switch c.Kind {
+		case ClippingBookmark:
+			g.Bookmarks++
+		case ClippingHighlight:
+			g.Highlights = addHighlight(g.Highlights, model.Highlight{
+				Text:     c.Text,
+				Location: c.Location,
+				Page:     c.Page,
+				AddedAt:  c.AddedAt,
+			})
+		case ClippingNote:
+			if h := highlightEndingAt(g.Highlights, c.Location); h != nil && h.Note == "" {
+				h.Note = c.Text
+				continue
+			}
+			g.Highlights = append(g.Highlights, model.Highlight{
+				Note:     c.Text,
+				Location: c.Location,
+				Page:     c.Page,
+				AddedAt:  c.AddedAt,
+			})
+		}
+	}
+
+	return groups
+}
+
+// addHighlight appends h unless it is already covered by a longer highlight at
+// the same place, and replaces earlier highlights that h extends. A short passage
+// that also occurs elsewhere in the book is kept.
+func addHighlight(highlights []model.Highlight, h model.Highlight) []model.Highlight {
+	for i := range highlights {
+		existing := &highlights[i]
+		if existing.Text == "" || !locationsOverlap(*existing, h) {
+			continue
+		}
+		if strings.Contains(existing.Text, h.Text) {
+			return highlights
+		}
+		if strings.Contains(h.Text, existing.Text) {
+			h.Note = firstNonEmpty(existing.Note, h.Note)
+			*existing = h
+			return highlights
+		}
+	}
+	return append(highlights, h)
+}
+
+// locationsOverlap reports whether two clippings cover the same place: their
+// location ranges ("120-125") intersect or, without locations, the page is the same
+func locationsOverlap(a, b model.Highlight) bool {
+	if aStart, aEnd, ok := locationRange(a.Location); ok {
+		if bStart, bEnd, ok := locationRange(b.Location); ok {
+			return aStart <= bEnd && bStart <= aEnd
+		}
+		return false
+	}
+	return a.Location == "" && b.Location == "" && a.Page != "" && a.Page == b.Page
+}
+
+// locationRange parses a Kindle location such as "120" or "120-125"
+func locationRange(location string) (start, end int, ok bool) {
+	first, last, isRange := strings.Cut(location, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, false
+	}
+	end = start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil {
+			return 0, 0, false
+		}
+		end = max(end, start)
+	}
+	return start, end, true
+}
+
+func highlightEndingAt(highlights []model.Highlight, location string) *model.Highlight {
+	if location == "" {
+		return nil
+	}
+	for i := len(highlights) - 1; i >= 0; i-- {
+		loc := highlights[i].Location
+		if highlights[i].Text == "" {
+			continue
+		}
+		if loc == location || strings.HasSuffix(loc, "-"+location) {
+			return &highlights[i]
+		}
+	}
+	return nil
+}
+
+// HighlightMatch is the result of matching one Kindle title against the shelf
+type HighlightMatch struct {
+	Group ClippingGroup
+	Book  *model.Book // nil when no book on the shelf matched
+	Added int
+}
+
+// MergeHighlights attaches grouped clippings to the matching books.
+// Books are matched by title (Kindle titles often carry a subtitle, so a shelf
+// title that prefixes the Kindle title also counts), with the author breaking ties.
+// A shelf title much shorter than the Kindle title only matches when the author
+// agrees, so "Go" does not claim "Good Strategy Bad Strategy".
+// Highlights the book already has are not added again, so reimporting is safe.
+func MergeHighlights(books []model.Book, groups []ClippingGroup) []HighlightMatch {
+	var matches []HighlightMatch
+	for _, g := range groups {
+		match := HighlightMatch{Group: g}
+		if i := matchBook(books, g.Title, g.Author); i >= 0 {
+			book := &books[i]
+			for _, h := range g.Highlights {
+				if hasHighlight(book.Highlights, h) {
+					continue
+				}
+				book.Highlights = append(book.Highlights, h)
+				match.Added++
+			}
+			match.Book = book
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func matchBook(books []model.Book, title, author string) int {
+	titleKey := compactKey(title)
+	authorKey := compactKey(author)
+
+	best, bestLen, bestAuthor := -1, 0, false
+	for i, book := range books {
+		bookKey := compactKey(book.Title)
+		if bookKey == "" || !strings.HasPrefix(titleKey, bookKey) {
+			continue
+		}
+		authorMatch := authorKey != "" && authorsOverlap(compactKey(book.Author), authorKey)
+		if bookKey != titleKey && !authorMatch && muchShorter(bookKey, titleKey) {
+			continue
+		}
+		if len(bookKey) > bestLen || (len(bookKey) == bestLen && authorMatch && !bestAuthor) {
+			best, bestLen, bestAuthor = i, len(bookKey), authorMatch
+		}
+	}
+	return best
+}
+
+// muchShorter reports whether the shelf title covers less than half of the Kindle title
+func muchShorter(bookKey, titleKey string) bool {
+	return 2*utf8.RuneCountInString(bookKey) < utf8.RuneCountInString(titleKey)
+}
+
+// authorsOverlap reports whether any part of the Kindle author appears in the shelf author.
+// Kindle writes names as "Martin, Robert C." or "Robert C. Martin", so the
+// comma-separated parts are compared one by one.
+func authorsOverlap(bookAuthors, kindleAuthors string) bool {
+	for _, part := range strings.FieldsFunc(kindleAuthors, func(r rune) bool { return r == ',' || r == ';' || r == '.' }) {
+		if len(part) >= 2 && strings.Contains(bookAuthors, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHighlight compares passages by text, and standalone notes by the note
+func hasHighlight(highlights []model.Highlight, h model.Highlight) bool {
+	for _, existing := range highlights {
+		if h.Text != "" && existing.Text == h.Text {
+			return true
+		}
+		if h.Text == "" && existing.Text == "" && existing.Note == h.Note {
+			return true
+		}
+	}
+	return false
+}
+
+// compactKey lowercases s and removes whitespace
+func compactKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
+}
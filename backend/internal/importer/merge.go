@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// Change describes what Merge did with one imported book
+type Change struct {
+	Book   model.Book
+	Added  bool
+	Fields []string // "field: old -> new" for updated books
+}
+
+// MergeResult is the merged shelf plus the changes against the original
+type MergeResult struct {
+	Books     []model.Book
+	Changes   []Change
+	Unchanged int
+}
+
+// Merge adds imported books to existing ones without duplicating them.
+// A book matches by ISBN first, then by title and author. Matched books only get
+// their empty fields filled in so hand-edited data is never overwritten.
+// New books get the next book-NNN IDs in reading order, so rerunning an import is stable.
+func Merge(existing, imported []model.Book) *MergeResult {
+	books := make([]model.Book, len(existing))
+	copy(books, existing)
+
+	byISBN := make(map[string]int)
+	byTitleAuthor := make(map[string]int)
+	for i, book := range books {
+		if isbn := NormalizeISBN(book.ISBN); isbn != "" {
+			byISBN[isbn] = i
+		}
+		byTitleAuthor[titleAuthorKey(book)] = i
+	}
+
+	incoming := make([]model.Book, len(imported))
+	copy(incoming, imported)
+	sort.SliceStable(incoming, func(i, j int) bool {
+		a, b := incoming[i].FinishedAt, incoming[j].FinishedAt
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return a < b
+	})
+
+	result := &MergeResult{}
+	for _, book := range incoming {
+		idx, found := byISBN[NormalizeISBN(book.ISBN)]
+		if !found {
+			idx, found = byTitleAuthor[titleAuthorKey(book)]
+		}
+
+		if found {
+			fields := fillEmpty(&books[idx], book)
+			if len(fields) == 0 {
+				result.Unchanged++
+				continue
+			}
+			result.Changes = append(result.Changes, Change{Book: books[idx], Fields: fields})
+			continue
+		}
+
+		book.ID = model.NextBookID(books)
+		book.Link = model.BookLink(book.Title, book.ID)
+		books = append(books, book)
+
+		idx = len(books) - 1
+		if isbn := NormalizeISBN(book.ISBN); isbn != "" {
+			byISBN[isbn] = idx
+		}
+		byTitleAuthor[titleAuthorKey(book)] = idx
+		result.Changes = append(result.Changes, Change{Book: book, Added: true})
+	}
+
+	result.Books = books
+	return result
+}
+
+// fillEmpty copies fields from src into dst where dst has none, returning what changed
+func fillEmpty(dst *model.Book, src model.Book) []string {
+	var fields []string
+	set := func(name string, dstField *string, value string) {
+		if *dstField != "" || value == "" {
+			return
+		}
+		fields = append(fields, fmt.Sprintf("%s: %q -> %q", name, *dstField, value))
+		*dstField = value
+	}
+
+	set("isbn", &dst.ISBN, src.ISBN)
+	set("finished_at", &dst.FinishedAt, src.FinishedAt)
+	set("private_notes", &dst.PrivateNotes, src.PrivateNotes)
+	set("language", &dst.Language, src.Language)
+	return fields
+}
+
+// titleAuthorKey ignores case and whitespace, since Japanese names are
+// written both with and without a space between family and given name
+func titleAuthorKey(book model.Book) string {
+	return compactKey(book.Title) + "\x00" + compactKey(book.Author)
+}
+
+// DetectLanguage returns "ja" when the text contains kana or kanji, otherwise "en"
+func DetectLanguage(text string) string {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return "ja"
+		}
+	}
+	return "en"
+}
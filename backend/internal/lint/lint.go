@@ -0,0 +1,299 @@
+// Package lint checks book data for mistakes that hand-editing books.json tends to introduce,
+// and repairs the ones that can be derived from other fields.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"talking-bookshelf/backend/internal/importer"
+	"talking-bookshelf/backend/internal/model"
+
+	"golang.org/x/text/width"
+)
+
+// Severity tells whether an issue breaks the app or is only suspicious
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is one problem found in the book data
+type Issue struct {
+	Path     string
+	Pos      Position // zero when the data didn't come from a file
+	BookID   string
+	Field    string
+	Severity Severity
+	Message  string
+	Fixable  bool
+}
+
+func (i Issue) String() string {
+	var b strings.Builder
+	if i.Path != "" {
+		b.WriteString(i.Path + ":")
+	}
+	if i.Pos.Line > 0 {
+		b.WriteString(i.Pos.String() + ":")
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "%s: ", i.Severity)
+	if i.BookID != "" {
+		b.WriteString(i.BookID + ": ")
+	}
+	b.WriteString(i.Message)
+	if i.Fixable {
+		b.WriteString(" (fixable)")
+	}
+	return b.String()
+}
+
+// HasErrors reports whether any issue has error severity
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	bookIDRegex     = regexp.MustCompile(`^book-\d+$`)
+	finishedAtRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	isbnPrefixRegex = regexp.MustCompile(`(?i)^isbn(?:-1[03])?[:\s]*`)
+)
+
+// finishedAtLayouts are the date spellings --fix can turn into YYYY-MM-DD
+var finishedAtLayouts = []string{
+	"2006-01-02", "2006-1-2", "2006/01/02", "2006/1/2", "2006.01.02", "2006.1.2", "2006年1月2日",
+}
+
+// CheckFile lints a books.json file, reporting issues with line and column
+func CheckFile(path string) ([]Issue, []model.Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read books file: %w", err)
+	}
+
+	var books []model.Book
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse books JSON: %w", err)
+	}
+	positions, err := locateBooks(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse books JSON: %w", err)
+	}
+
+	issues := check(books, positions)
+	for i := range issues {
+		issues[i].Path = path
+	}
+	return issues, books, nil
+}
+
+// CheckBooks lints books that didn't come from a file (e.g. the sqlite store)
+func CheckBooks(books []model.Book) []Issue {
+	return check(books, nil)
+}
+
+// CheckBook lints a single book, e.g. one about to be saved through the admin API
+func CheckBook(book model.Book) []Issue {
+	return check([]model.Book{book}, nil)
+}
+
+func check(books []model.Book, positions []bookPositions) []Issue {
+	var issues []Issue
+	firstSeen := make(map[string]Position)
+	idCounts := countIDs(books)
+
+	for i, book := range books {
+		var bp bookPositions
+		if i < len(positions) {
+			bp = positions[i]
+		}
+		report := func(field string, severity Severity, fixable bool, format string, args ...any) {
+			pos, ok := bp.fields[field]
+			if !ok {
+				pos = bp.object
+			}
+			issues = append(issues, Issue{
+				Pos:      pos,
+				BookID:   book.ID,
+				Field:    field,
+				Severity: severity,
+				Message:  field + ": " + fmt.Sprintf(format, args...),
+				Fixable:  fixable,
+			})
+		}
+
+		switch {
+		case book.ID == "":
+			report("id", SeverityError, false, "book #%d has no id", i+1)
+		case !bookIDRegex.MatchString(book.ID):
+			report("id", SeverityWarning, false, "%q should look like book-001", book.ID)
+		}
+		if book.ID != "" {
+			if first, dup := firstSeen[book.ID]; dup {
+				if first.Line > 0 {
+					report("id", SeverityError, false, "duplicate id (first defined at line %d)", first.Line)
+				} else {
+					report("id", SeverityError, false, "duplicate id")
+				}
+			} else {
+				firstSeen[book.ID] = bp.fields["id"]
+			}
+		}
+
+		if book.Title == "" {
+			report("title", SeverityError, false, "missing title")
+		} else if !model.LinkSafeTitle(book.Title) {
+			report("title", SeverityError, false, "%q contains \"::\" or \"]\", which breaks [book::title::id] annotations", book.Title)
+		}
+
+		if book.ID != "" && book.Title != "" {
+			if want := model.BookLink(book.Title, book.ID); book.Link != want {
+				report("link", SeverityError, linkFixable(book, idCounts), "%q does not match title and id (want %q)", book.Link, want)
+			}
+		}
+
+		if book.FinishedAt != "" && !validDate(book.FinishedAt) {
+			_, fixable := normalizeDate(book.FinishedAt)
+			report("finished_at", SeverityError, fixable, "%q is not a YYYY-MM-DD date", book.FinishedAt)
+		}
+
+		if book.StartedAt != "" && !validDate(book.StartedAt) {
+			_, fixable := normalizeDate(book.StartedAt)
+			report("started_at", SeverityError, fixable, "%q is not a YYYY-MM-DD date", book.StartedAt)
+		}
+
+		if book.Status != "" && !model.ValidStatus(book.Status) {
+			report("status", SeverityError, false, "%q must be one of %s", book.Status, strings.Join(model.Statuses, ", "))
+		}
+		if book.Rating < 0 || book.Rating > 5 {
+			report("rating", SeverityError, false, "%d must be between 1 and 5 (0 = not rated)", book.Rating)
+		}
+		if p := book.Progress; p != nil && (p.Percent > 100 || (p.Pages > 0 && p.Page > p.Pages)) {
+			report("progress", SeverityWarning, false, "%+v is past the end of the book", *p)
+		}
+
+		for _, genre := range book.Genres {
+			if !model.ValidGenre(genre) {
+				report("genres", SeverityError, false, "%q is not in the genre list (use tags for free-form labels)", genre)
+			}
+		}
+
+		if book.Language != "ja" && book.Language != "en" {
+			report("language", SeverityError, true, "%q must be \"ja\" or \"en\"", book.Language)
+		}
+
+		if book.ISBN != "" {
+			if !model.ValidISBN(normalizeISBN(book.ISBN)) {
+				report("isbn", SeverityError, false, "%q has an invalid check digit or length", book.ISBN)
+			} else if normalized := normalizeISBN(book.ISBN); normalized != book.ISBN {
+				report("isbn", SeverityWarning, true, "%q is not normalized (want %q)", book.ISBN, normalized)
+			}
+		}
+	}
+
+	return issues
+}
+
+// Fix repairs the fixable issues in place and describes each change
+func Fix(books []model.Book) []string {
+	var changes []string
+	set := func(book *model.Book, field string, dst *string, value string) {
+		if *dst == value {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s: %q -> %q", book.ID, field, *dst, value))
+		*dst = value
+	}
+
+	idCounts := countIDs(books)
+	for i := range books {
+		book := &books[i]
+		if linkFixable(*book, idCounts) {
+			set(book, "link", &book.Link, model.BookLink(book.Title, book.ID))
+		}
+		if book.FinishedAt != "" && !validDate(book.FinishedAt) {
+			if date, ok := normalizeDate(book.FinishedAt); ok {
+				set(book, "finished_at", &book.FinishedAt, date)
+			}
+		}
+		if book.StartedAt != "" && !validDate(book.StartedAt) {
+			if date, ok := normalizeDate(book.StartedAt); ok {
+				set(book, "started_at", &book.StartedAt, date)
+			}
+		}
+		if book.Language != "ja" && book.Language != "en" {
+			set(book, "language", &book.Language, normalizeLanguage(book))
+		}
+		if book.ISBN != "" && model.ValidISBN(normalizeISBN(book.ISBN)) {
+			set(book, "isbn", &book.ISBN, normalizeISBN(book.ISBN))
+		}
+	}
+	return changes
+}
+
+// linkFixable reports whether the link can be regenerated safely. With a duplicate
+// id or a title that breaks annotations the owner has to decide what is right first.
+func linkFixable(book model.Book, idCounts map[string]int) bool {
+	return book.ID != "" && book.Title != "" && idCounts[book.ID] == 1 &&
+		!strings.Contains(book.Title, "::") && !strings.Contains(book.Title, "]")
+}
+
+func countIDs(books []model.Book) map[string]int {
+	counts := make(map[string]int, len(books))
+	for _, book := range books {
+		counts[book.ID]++
+	}
+	return counts
+}
+
+func validDate(s string) bool {
+	if !finishedAtRegex.MatchString(s) {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+func normalizeDate(s string) (string, bool) {
+	s = strings.TrimSpace(width.Narrow.String(s))
+	for _, layout := range finishedAtLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// normalizeLanguage maps common spellings to ja/en and falls back to detecting the title's script
+func normalizeLanguage(book *model.Book) string {
+	switch strings.ToLower(strings.TrimSpace(book.Language)) {
+	case "ja", "jp", "jpn", "japanese", "日本語":
+		return "ja"
+	case "en", "eng", "english", "英語":
+		return "en"
+	}
+	return importer.DetectLanguage(book.Title + " " + book.Author)
+}
+
+// normalizeISBN keeps the hyphenation the owner chose but drops an "ISBN" prefix,
+// full-width characters and spaces, and upper-cases the X check digit
+func normalizeISBN(s string) string {
+	s = width.Narrow.String(strings.TrimSpace(s))
+	s = isbnPrefixRegex.ReplaceAllString(s, "")
+	s = strings.Join(strings.Fields(s), "-")
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Position is a 1-based line and column in the data file
+type Position struct {
+	Line   int
+	Column int
+}
+
+// bookPositions records where one book object and each of its keys start
+type bookPositions struct {
+	object Position
+	fields map[string]Position
+}
+
+// locateBooks walks a books.json array and returns the positions of every
+// book object and key, in the same order as the books
+func locateBooks(data []byte) ([]bookPositions, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected a JSON array of books")
+	}
+
+	var books []bookPositions
+	for dec.More() {
+		objStart := skipSeparators(data, dec.InputOffset())
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, fmt.Errorf("%s: expected a book object", offsetPosition(data, objStart))
+		}
+
+		bp := bookPositions{object: offsetPosition(data, objStart), fields: make(map[string]Position)}
+		for dec.More() {
+			keyStart := skipSeparators(data, dec.InputOffset())
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := tok.(string)
+
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			bp.fields[key] = offsetPosition(data, keyStart)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		books = append(books, bp)
+	}
+	return books, nil
+}
+
+// skipSeparators moves past whitespace, commas and colons to the next token
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func offsetPosition(data []byte, offset int64) Position {
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return Position{Line: line, Column: utf8.RuneCount(before[lineStart:]) + 1}
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
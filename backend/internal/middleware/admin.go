@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth returns a middleware that requires "Authorization: Bearer <token>"
+// for owner-only endpoints under /api/admin
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		provided, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(provided)), []byte(token)) != 1 {
+			log.Printf("[SECURITY] Admin request rejected path=%s", c.Request.URL.Path)
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+				"code":  "UNAUTHORIZED",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -108,3 +108,24 @@ func RateLimitMiddleware(ipLimiter *IPRateLimiter, quota *DailyQuota) gin.Handle
 		c.Next()
 	}
 }
+
+// ShelfRateLimitMiddleware applies RateLimitMiddleware with separate limiters per
+// shelf (the :slug route parameter). newLimits is called once for each slug; the
+// slug must already be validated (see handler.RequireShelf) so the map stays bounded.
+func ShelfRateLimitMiddleware(newLimits func(slug string) (*IPRateLimiter, *DailyQuota)) gin.HandlerFunc {
+	var mu sync.Mutex
+	perShelf := make(map[string]gin.HandlerFunc)
+
+	return func(c *gin.Context) {
+		slug := c.Param("slug")
+		mu.Lock()
+		limit, ok := perShelf[slug]
+		if !ok {
+			ipLimiter, quota := newLimits(slug)
+			limit = RateLimitMiddleware(ipLimiter, quota)
+			perShelf[slug] = limit
+		}
+		mu.Unlock()
+		limit(c)
+	}
+}
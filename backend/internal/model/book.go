@@ -1,35 +1,203 @@
 package model
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
 type Book struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	Author       string `json:"author"`
-	ISBN         string `json:"isbn"`
-	Cover        string `json:"cover"`
-	FinishedAt   string `json:"finished_at"`
-	PrivateNotes string `json:"private_notes,omitempty"`
-	Link         string `json:"link"`     // [book::タイトル::book-id] format for AI to use directly
-	Language     string `json:"language"` // "ja" or "en"
+	ID           string      `json:"id"`
+	Title        string      `json:"title"`
+	Author       string      `json:"author"`
+	ISBN         string      `json:"isbn"`
+	Cover        string      `json:"cover"`
+	FinishedAt   string      `json:"finished_at"`
+	PrivateNotes string      `json:"private_notes,omitempty"`
+	Link         string      `json:"link"`     // [book::タイトル::book-id] format for AI to use directly
+	Language     string      `json:"language"` // "ja" or "en"
+	Highlights   []Highlight `json:"highlights,omitempty"`
+	Excerpt      string      `json:"excerpt,omitempty"` // owner-approved public blurb (feeds, book pages)
+	Status       string      `json:"status,omitempty"`  // StatusToRead, StatusReading, StatusFinished or StatusAbandoned
+	StartedAt    string      `json:"started_at,omitempty"`
+	Progress     *Progress   `json:"progress,omitempty"`
+	Rating       int         `json:"rating,omitempty"`      // 1-5, 0 = not rated
+	Tags         []string    `json:"tags,omitempty"`        // free-form labels
+	Genres       []string    `json:"genres,omitempty"`      // IDs from the controlled Genres list
+	Collections  []string    `json:"collections,omitempty"` // owner-defined named shelves, e.g. "Favourites 2024"
+	PublishYear  int         `json:"publish_year,omitempty"`
+	PageCount    int         `json:"page_count,omitempty"`
+	Subjects     []string    `json:"subjects,omitempty"` // publisher / library subjects, e.g. from Open Library
+}
+
+// Reading status values
+const (
+	StatusToRead    = "to-read"
+	StatusReading   = "reading"
+	StatusFinished  = "finished"
+	StatusAbandoned = "abandoned"
+)
+
+// Statuses lists the reading statuses in lifecycle order
+var Statuses = []string{StatusToRead, StatusReading, StatusFinished, StatusAbandoned}
+
+// ValidStatus reports whether s is one of Statuses
+func ValidStatus(s string) bool {
+	return slices.Contains(Statuses, s)
+}
+
+// ReadingStatus returns the status, treating books saved before statuses existed as finished
+func (b *Book) ReadingStatus() string {
+	if b.Status == "" {
+		return StatusFinished
+	}
+	return b.Status
+}
+
+// Progress is how far into a book the owner is: a page count, a percentage or both
+type Progress struct {
+	Page    int `json:"page,omitempty"`
+	Pages   int `json:"pages,omitempty"` // total pages, so Page can be shown as a percentage
+	Percent int `json:"percent,omitempty"`
+}
+
+// Percentage returns Percent, or Page/Pages when only pages are known (0 if unknown)
+func (p *Progress) Percentage() int {
+	if p == nil {
+		return 0
+	}
+	if p.Percent > 0 || p.Pages <= 0 {
+		return p.Percent
+	}
+	return min(p.Page*100/p.Pages, 100)
+}
+
+// Highlight is a passage the owner marked while reading (e.g. imported from Kindle)
+type Highlight struct {
+	Text     string   `json:"text"`
+	Location string   `json:"location,omitempty"` // Kindle location, e.g. "150-152"
+	Page     string   `json:"page,omitempty"`
+	Note     string   `json:"note,omitempty"` // owner's comment attached to the passage
+	Tags     []string `json:"tags,omitempty"`
+	AddedAt  string   `json:"added_at,omitempty"`
 }
 
 type BookResponse struct {
-	ID         string `json:"id"`
-	Title      string `json:"title"`
-	Author     string `json:"author"`
-	ISBN       string `json:"isbn"`
-	Cover      string `json:"cover"`
-	FinishedAt string `json:"finished_at"`
-	Language   string `json:"language"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	ISBN        string    `json:"isbn"`
+	Cover       string    `json:"cover"`
+	FinishedAt  string    `json:"finished_at"`
+	Language    string    `json:"language"`
+	Excerpt     string    `json:"excerpt,omitempty"`
+	Status      string    `json:"status"`
+	StartedAt   string    `json:"started_at,omitempty"`
+	Progress    *Progress `json:"progress,omitempty"`
+	Rating      int       `json:"rating,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Genres      []string  `json:"genres,omitempty"`
+	Collections []string  `json:"collections,omitempty"`
+	PublishYear int       `json:"publish_year,omitempty"`
+	PageCount   int       `json:"page_count,omitempty"`
+	Subjects    []string  `json:"subjects,omitempty"`
 }
 
 func (b *Book) ToResponse() BookResponse {
 	return BookResponse{
-		ID:         b.ID,
-		Title:      b.Title,
-		Author:     b.Author,
-		ISBN:       b.ISBN,
-		Cover:      b.Cover,
-		FinishedAt: b.FinishedAt,
-		Language:   b.Language,
+		ID:          b.ID,
+		Title:       b.Title,
+		Author:      b.Author,
+		ISBN:        b.ISBN,
+		Cover:       cmp.Or(b.Cover, CoverPath(b.ID)),
+		FinishedAt:  b.FinishedAt,
+		Language:    b.Language,
+		Excerpt:     b.Excerpt,
+		Status:      b.ReadingStatus(),
+		StartedAt:   b.StartedAt,
+		Progress:    b.Progress,
+		Rating:      b.Rating,
+		Tags:        b.Tags,
+		Genres:      b.Genres,
+		Collections: b.Collections,
+		PublishYear: b.PublishYear,
+		PageCount:   b.PageCount,
+		Subjects:    b.Subjects,
+	}
+}
+
+// CoverPath is the server path of the book's local cover, thumbnail or placeholder
+func CoverPath(id string) string {
+	return "/covers/" + id
+}
+
+// BookLink builds the [book::タイトル::book-id] annotation the agent uses to reference a book
+func BookLink(title, id string) string {
+	return fmt.Sprintf("[book::%s::%s]", title, id)
+}
+
+// LinkSafeTitle reports whether title can be written in a [book::title::id] annotation
+func LinkSafeTitle(title string) bool {
+	return !strings.Contains(title, "::") && !strings.Contains(title, "]")
+}
+
+// QuoteRef builds the [quote::book-id::n] annotation for the book's n-th highlight (1-based)
+func QuoteRef(bookID string, n int) string {
+	return fmt.Sprintf("[quote::%s::%d]", bookID, n)
+}
+
+// NextBookID returns the next free "book-NNN" ID after the highest existing one
+func NextBookID(books []Book) string {
+	maxNum := 0
+	for _, book := range books {
+		numStr, ok := strings.CutPrefix(book.ID, "book-")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(numStr); err == nil && n > maxNum {
+			maxNum = n
+		}
+	}
+	return fmt.Sprintf("book-%03d", maxNum+1)
+}
+
+// Authors splits the author field into individual names
+func (b *Book) Authors() []string {
+	return SplitAuthors(b.Author)
+}
+
+// SplitAuthors splits an author field ("A, B", "A、B", "A; B" or "A & B") into individual names
+func SplitAuthors(author string) []string {
+	var authors []string
+	for _, name := range strings.FieldsFunc(author, isAuthorSeparator) {
+		if name = NormalizeAuthor(name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+func isAuthorSeparator(r rune) bool {
+	switch r {
+	case ',', '、', '，', ';', '；', '&', '＆':
+		return true
 	}
+	return false
+}
+
+// NormalizeAuthor folds full-width letters and spaces (NFKC) and collapses whitespace
+func NormalizeAuthor(name string) string {
+	return strings.Join(strings.Fields(norm.NFKC.String(name)), " ")
+}
+
+// AuthorKey identifies an author regardless of case, periods, middle dots and spacing, so
+// "Robert C. Martin" matches "robert c martin" and "岸見 一郎" matches "岸見一郎"
+func AuthorKey(name string) string {
+	name = strings.ToLower(NormalizeAuthor(name))
+	name = strings.NewReplacer(".", "", "・", "", "·", "").Replace(name)
+	return strings.Join(strings.Fields(name), "")
 }
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Genre is an entry of the controlled genre list
+type Genre struct {
+	ID string `json:"id"`
+	Ja string `json:"ja"`
+	En string `json:"en"`
+}
+
+// Genres is the controlled list books may use in Book.Genres.
+// Free-form labels belong in Book.Tags instead.
+var Genres = []Genre{
+	{ID: "software-engineering", Ja: "ソフトウェア開発", En: "Software engineering"},
+	{ID: "computer-science", Ja: "コンピュータサイエンス", En: "Computer science"},
+	{ID: "design", Ja: "デザイン", En: "Design"},
+	{ID: "business", Ja: "ビジネス", En: "Business"},
+	{ID: "management", Ja: "マネジメント", En: "Management"},
+	{ID: "economics", Ja: "経済", En: "Economics"},
+	{ID: "science", Ja: "科学", En: "Science"},
+	{ID: "history", Ja: "歴史", En: "History"},
+	{ID: "philosophy", Ja: "哲学", En: "Philosophy"},
+	{ID: "psychology", Ja: "心理学", En: "Psychology"},
+	{ID: "self-help", Ja: "自己啓発", En: "Self-help"},
+	{ID: "biography", Ja: "伝記", En: "Biography"},
+	{ID: "essay", Ja: "エッセイ", En: "Essay"},
+	{ID: "fiction", Ja: "小説", En: "Fiction"},
+	{ID: "sf", Ja: "SF", En: "Science fiction"},
+	{ID: "mystery", Ja: "ミステリー", En: "Mystery"},
+	{ID: "comics", Ja: "漫画", En: "Comics"},
+	{ID: "art", Ja: "アート", En: "Art"},
+}
+
+// ValidGenre reports whether id is in Genres
+func ValidGenre(id string) bool {
+	return slices.ContainsFunc(Genres, func(g Genre) bool { return g.ID == id })
+}
+
+// GenreLabel returns the genre's name in lang ("ja" or "en"), or the id if it is unknown
+func GenreLabel(id, lang string) string {
+	for _, g := range Genres {
+		if g.ID == id {
+			if lang == "ja" {
+				return g.Ja
+			}
+			return g.En
+		}
+	}
+	return id
+}
+
+// HasLabel reports whether labels (tags or collections) contain label, ignoring case and surrounding spaces
+func HasLabel(labels []string, label string) bool {
+	label = strings.TrimSpace(label)
+	return slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(strings.TrimSpace(l), label) })
+}
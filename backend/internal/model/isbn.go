@@ -0,0 +1,72 @@
+package model
+
+import "strings"
+
+// ISBNDigits strips hyphens and spaces from an ISBN, keeping a trailing X check digit
+func ISBNDigits(isbn string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(isbn) {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidISBN reports whether isbn is an ISBN-10 or ISBN-13 with a correct check digit
+func ValidISBN(isbn string) bool {
+	digits := ISBNDigits(isbn)
+	switch len(digits) {
+	case 10:
+		sum := 0
+		for i, r := range digits {
+			var v int
+			switch {
+			case r == 'X' && i == 9:
+				v = 10
+			case r >= '0' && r <= '9':
+				v = int(r - '0')
+			default:
+				return false
+			}
+			sum += v * (10 - i)
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i, r := range digits {
+			if r < '0' || r > '9' {
+				return false
+			}
+			if i%2 == 0 {
+				sum += int(r - '0')
+			} else {
+				sum += 3 * int(r-'0')
+			}
+		}
+		return sum%10 == 0
+	}
+	return false
+}
+
+// ISBN13 returns the 13-digit form of a valid ISBN-10 or ISBN-13, or "" if isbn is invalid
+func ISBN13(isbn string) string {
+	if !ValidISBN(isbn) {
+		return ""
+	}
+	digits := ISBNDigits(isbn)
+	if len(digits) == 13 {
+		return digits
+	}
+
+	digits = "978" + digits[:9]
+	sum := 0
+	for i, r := range digits {
+		if i%2 == 0 {
+			sum += int(r - '0')
+		} else {
+			sum += 3 * int(r-'0')
+		}
+	}
+	return digits + string(rune('0'+(10-sum%10)%10))
+}
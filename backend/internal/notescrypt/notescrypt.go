@@ -0,0 +1,247 @@
+// Package notescrypt encrypts private notes at rest.
+//
+// Each note is sealed with AES-256-GCM under its own random data key (DEK),
+// and the DEK is sealed with the key-encryption key (KEK) from NOTES_KEY or
+// NOTES_KEY_FILE. Rotating the KEK only rewraps the DEKs. A sealed note is a
+// single string so it fits in the existing private_notes field:
+//
+//	enc:v1:<key id>:<wrapped DEK>:<ciphertext>
+package notescrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Prefix marks an encrypted note
+const Prefix = "enc:v1:"
+
+// KeySize is the length of a KEK in bytes (AES-256)
+const KeySize = 32
+
+var (
+	// ErrWrongKey is returned when a note was sealed under a different KEK
+	ErrWrongKey = errors.New("note was encrypted with a different key")
+	// ErrMalformed is returned for values that start with Prefix but can't be parsed
+	ErrMalformed = errors.New("malformed encrypted note")
+)
+
+var b64 = base64.RawURLEncoding
+
+// dekAAD binds wrapped DEKs to their purpose
+var dekAAD = []byte("notescrypt dek v1")
+
+// Key is a key-encryption key
+type Key struct {
+	id  string
+	kek cipher.AEAD
+}
+
+// NewKey creates a Key from KeySize raw bytes
+func NewKey(raw []byte) (*Key, error) {
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("notes key must be %d bytes, got %d", KeySize, len(raw))
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return &Key{id: hex.EncodeToString(sum[:4]), kek: aead}, nil
+}
+
+// ParseKey decodes a base64 key as printed by GenerateKey
+func ParseKey(text string) (*Key, error) {
+	text = strings.TrimSpace(text)
+	raw, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		if raw, err = base64.RawURLEncoding.DecodeString(text); err != nil {
+			return nil, fmt.Errorf("notes key is not valid base64")
+		}
+	}
+	return NewKey(raw)
+}
+
+// LoadKeyFile reads a base64 key from a file
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes key file: %w", err)
+	}
+	return ParseKey(string(data))
+}
+
+// LoadKeyFromEnv returns the key from NOTES_KEY, or from the file named by
+// NOTES_KEY_FILE. It returns nil without error when neither is set.
+func LoadKeyFromEnv() (*Key, error) {
+	if v := os.Getenv("NOTES_KEY"); v != "" {
+		return ParseKey(v)
+	}
+	if path := os.Getenv("NOTES_KEY_FILE"); path != "" {
+		return LoadKeyFile(path)
+	}
+	return nil, nil
+}
+
+// GenerateKey returns a new random key in base64
+func GenerateKey() (string, error) {
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// ID identifies the key in sealed notes (first bytes of its SHA-256)
+func (k *Key) ID() string {
+	return k.id
+}
+
+// IsEncrypted reports whether a note value is sealed
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// KeyID returns the id of the key a sealed note was encrypted with
+func KeyID(value string) (string, error) {
+	p, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	return p.keyID, nil
+}
+
+// Encrypt seals a note. bookID is authenticated so a sealed note can't be moved to another book.
+func (k *Key) Encrypt(plaintext, bookID string) (string, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext), []byte(bookID))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.kek, dek, dekAAD)
+	if err != nil {
+		return "", err
+	}
+	return format(sealed{keyID: k.id, wrappedDEK: wrapped, ciphertext: ciphertext}), nil
+}
+
+// Decrypt opens a sealed note of the given book
+func (k *Key) Decrypt(value, bookID string) (string, error) {
+	p, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dek, err := k.unwrap(p)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, p.ciphertext, []byte(bookID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt note: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-seals the data key of a note under next, leaving the note ciphertext as is
+func (k *Key) Rewrap(value string, next *Key) (string, error) {
+	p, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dek, err := k.unwrap(p)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(next.kek, dek, dekAAD)
+	if err != nil {
+		return "", err
+	}
+	p.keyID = next.id
+	p.wrappedDEK = wrapped
+	return format(p), nil
+}
+
+func (k *Key) unwrap(p sealed) ([]byte, error) {
+	if p.keyID != k.id {
+		return nil, fmt.Errorf("%w (note key %s, configured key %s)", ErrWrongKey, p.keyID, k.id)
+	}
+	dek, err := open(k.kek, p.wrappedDEK, dekAAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dek, nil
+}
+
+// sealed is the parsed form of an encrypted note
+type sealed struct {
+	keyID      string
+	wrappedDEK []byte
+	ciphertext []byte
+}
+
+func format(p sealed) string {
+	return Prefix + p.keyID + ":" + b64.EncodeToString(p.wrappedDEK) + ":" + b64.EncodeToString(p.ciphertext)
+}
+
+func parse(value string) (sealed, error) {
+	if !IsEncrypted(value) {
+		return sealed{}, ErrMalformed
+	}
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return sealed{}, ErrMalformed
+	}
+	wrapped, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return sealed{}, ErrMalformed
+	}
+	ciphertext, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return sealed{}, ErrMalformed
+	}
+	return sealed{keyID: parts[0], wrappedDEK: wrapped, ciphertext: ciphertext}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
@@ -0,0 +1,165 @@
+// Package opds renders the bookshelf as an OPDS 1.2 catalog (Atom feeds).
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// Media types used by OPDS 1.2
+const (
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	OpenSearchType  = "application/opensearchdescription+xml"
+)
+
+// Link relations
+const (
+	RelSelf       = "self"
+	RelStart      = "start"
+	RelUp         = "up"
+	RelSearch     = "search"
+	RelSubsection = "subsection"
+	RelNext       = "next"
+	RelPrevious   = "previous"
+	RelAlternate  = "alternate"
+	RelImage      = "http://opds-spec.org/image"
+	RelThumbnail  = "http://opds-spec.org/image/thumbnail"
+)
+
+// Feed is an Atom feed. Kind is NavigationType or AcquisitionType.
+type Feed struct {
+	XMLName      xml.Name `xml:"feed"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	XmlnsDC      string   `xml:"xmlns:dc,attr"`
+	XmlnsOPDS    string   `xml:"xmlns:opds,attr"`
+	ID           string   `xml:"id"`
+	Title        string   `xml:"title"`
+	Updated      string   `xml:"updated"`
+	Author       *Person  `xml:"author,omitempty"`
+	Links        []Link   `xml:"link"`
+	Entries      []Entry  `xml:"entry"`
+	TotalResults int      `xml:"http://a9.com/-/spec/opensearch/1.1/ totalResults,omitempty"`
+	StartIndex   int      `xml:"http://a9.com/-/spec/opensearch/1.1/ startIndex,omitempty"`
+	ItemsPerPage int      `xml:"http://a9.com/-/spec/opensearch/1.1/ itemsPerPage,omitempty"`
+}
+
+// Entry is a navigation entry (a link to another feed) or a catalog entry (a book)
+type Entry struct {
+	ID         string   `xml:"id"`
+	Title      string   `xml:"title"`
+	Updated    string   `xml:"updated"`
+	Authors    []Person `xml:"author"`
+	Identifier string   `xml:"dc:identifier,omitempty"`
+	Language   string   `xml:"dc:language,omitempty"`
+	Content    *Content `xml:"content,omitempty"`
+	Links      []Link   `xml:"link"`
+}
+
+// Person is an Atom person construct
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// Link is an Atom link
+type Link struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+// Content is a plain text Atom content
+type Content struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// NewFeed creates a feed with the namespaces and the self/start/search links every catalog page carries
+func NewFeed(id, title, self, kind, start, search string, updated time.Time) *Feed {
+	return &Feed{
+		Xmlns:     "http://www.w3.org/2005/Atom",
+		XmlnsDC:   "http://purl.org/dc/terms/",
+		XmlnsOPDS: "http://opds-spec.org/2010/catalog",
+		ID:        id,
+		Title:     title,
+		Updated:   formatTime(updated),
+		Links: []Link{
+			{Rel: RelSelf, Href: self, Type: kind},
+			{Rel: RelStart, Href: start, Type: NavigationType},
+			{Rel: RelSearch, Href: search, Type: OpenSearchType},
+		},
+	}
+}
+
+// NavigationEntry links to a sub-catalog
+func NavigationEntry(id, title, href, kind, summary string, updated time.Time) Entry {
+	entry := Entry{
+		ID:      id,
+		Title:   title,
+		Updated: formatTime(updated),
+		Links:   []Link{{Rel: RelSubsection, Href: href, Type: kind}},
+	}
+	if summary != "" {
+		entry.Content = &Content{Type: "text", Text: summary}
+	}
+	return entry
+}
+
+// BookEntry describes a book. There are no acquisition links: the shelf lists
+// what the owner has read, it doesn't distribute the books themselves.
+// detailHref is the JSON detail of the book; cover links are added when the book has one.
+func BookEntry(book model.Book, detailHref string, updated time.Time) Entry {
+	entry := Entry{
+		ID:       BookID(book),
+		Title:    book.Title,
+		Updated:  formatTime(updated),
+		Language: book.Language,
+		Links:    []Link{{Rel: RelAlternate, Href: detailHref, Type: "application/json"}},
+	}
+	for _, name := range book.Authors() {
+		entry.Authors = append(entry.Authors, Person{Name: name})
+	}
+	if model.ValidISBN(book.ISBN) {
+		entry.Identifier = "urn:isbn:" + model.ISBNDigits(book.ISBN)
+	}
+	if book.FinishedAt != "" {
+		entry.Content = &Content{Type: "text", Text: fmt.Sprintf("Finished: %s", book.FinishedAt)}
+	}
+	if book.Cover != "" {
+		entry.Links = append(entry.Links,
+			Link{Rel: RelImage, Href: book.Cover, Type: imageType(book.Cover)},
+			Link{Rel: RelThumbnail, Href: book.Cover, Type: imageType(book.Cover)},
+		)
+	}
+	return entry
+}
+
+// BookID is the Atom id of a book: its ISBN URN when it has a valid ISBN, so
+// that reading apps can match it with their own copy
+func BookID(book model.Book) string {
+	if model.ValidISBN(book.ISBN) {
+		return "urn:isbn:" + model.ISBNDigits(book.ISBN)
+	}
+	return "urn:talking-bookshelf:book:" + book.ID
+}
+
+// Marshal encodes the feed with an XML declaration
+func (f *Feed) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
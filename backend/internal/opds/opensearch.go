@@ -0,0 +1,64 @@
+package opds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// OpenSearchDescription tells OPDS clients how to build search URLs
+type OpenSearchDescription struct {
+	XMLName        xml.Name      `xml:"OpenSearchDescription"`
+	Xmlns          string        `xml:"xmlns,attr"`
+	ShortName      string        `xml:"ShortName"`
+	Description    string        `xml:"Description"`
+	InputEncoding  string        `xml:"InputEncoding"`
+	OutputEncoding string        `xml:"OutputEncoding"`
+	URL            OpenSearchURL `xml:"Url"`
+}
+
+// OpenSearchURL is a search URL template ({searchTerms} is replaced by the query)
+type OpenSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// NewOpenSearchDescription describes a search endpoint returning acquisition feeds
+func NewOpenSearchDescription(shortName, description, template string) *OpenSearchDescription {
+	return &OpenSearchDescription{
+		Xmlns:          "http://a9.com/-/spec/opensearch/1.1/",
+		ShortName:      shortName,
+		Description:    description,
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URL:            OpenSearchURL{Type: AcquisitionType, Template: template},
+	}
+}
+
+// Marshal encodes the description with an XML declaration
+func (d *OpenSearchDescription) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenSearch description: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// imageType guesses the media type of a cover from its extension
+func imageType(href string) string {
+	ext := strings.ToLower(path.Ext(strings.SplitN(href, "?", 2)[0]))
+	switch ext {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	case ".svg":
+		return "image/svg+xml"
+	}
+	return ""
+}
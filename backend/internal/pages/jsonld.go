@@ -0,0 +1,93 @@
+package pages
+
+import (
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/portfolio"
+)
+
+const schemaContext = "https://schema.org"
+
+// PersonJSONLD describes the shelf owner from portfolio.About, linking the social profiles
+func PersonJSONLD(about portfolio.About, social []portfolio.SocialLink, url string) map[string]any {
+	person := map[string]any{
+		"@context": schemaContext,
+		"@type":    "Person",
+		"@id":      url + "#owner",
+		"name":     about.Name,
+		"url":      url,
+	}
+	if about.Title != "" {
+		person["jobTitle"] = about.Title
+	}
+	if about.Tagline != "" {
+		person["description"] = about.Tagline
+	}
+	if about.Location != "" {
+		person["homeLocation"] = map[string]any{"@type": "Place", "name": about.Location}
+	}
+	var sameAs []string
+	for _, s := range social {
+		if s.URL != "" {
+			sameAs = append(sameAs, s.URL)
+		}
+	}
+	if len(sameAs) > 0 {
+		person["sameAs"] = sameAs
+	}
+	return person
+}
+
+// BookJSONLD describes a book with its public metadata. ownerURL links the
+// owner's Person node; description is the public blurb (never private notes).
+func BookJSONLD(book model.BookResponse, url, image, description, ownerURL string) map[string]any {
+	data := map[string]any{
+		"@context": schemaContext,
+		"@type":    "Book",
+		"@id":      url + "#book",
+		"name":     book.Title,
+		"url":      url,
+	}
+
+	var authors []map[string]any
+	for _, name := range model.SplitAuthors(book.Author) {
+		authors = append(authors, map[string]any{"@type": "Person", "name": name})
+	}
+	if len(authors) > 0 {
+		data["author"] = authors
+	}
+	if model.ValidISBN(book.ISBN) {
+		data["isbn"] = model.ISBNDigits(book.ISBN)
+	}
+	if book.Language != "" {
+		data["inLanguage"] = book.Language
+	}
+	if image != "" {
+		data["image"] = image
+	}
+	if description != "" {
+		data["description"] = description
+	}
+	if ownerURL != "" {
+		// The page is the owner's reading record of the book
+		data["mainEntityOfPage"] = map[string]any{
+			"@type":  "WebPage",
+			"@id":    url,
+			"author": map[string]any{"@id": ownerURL + "#owner"},
+		}
+	}
+	return data
+}
+
+// ItemListJSONLD lists the books on the shelf, linking each book page
+func ItemListJSONLD(name string, urls []string) map[string]any {
+	items := make([]map[string]any, len(urls))
+	for i, url := range urls {
+		items[i] = map[string]any{"@type": "ListItem", "position": i + 1, "url": url}
+	}
+	return map[string]any{
+		"@context":        schemaContext,
+		"@type":           "ItemList",
+		"name":            name,
+		"itemListElement": items,
+	}
+}
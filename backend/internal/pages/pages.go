@@ -0,0 +1,130 @@
+// Package pages server-renders the public HTML pages (home and book pages)
+// into the built index.html, so crawlers and link previews see real content
+// before the React app boots on top of it.
+package pages
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+const rootElement = `<div id="root"></div>`
+
+var (
+	titleRegex   = regexp.MustCompile(`(?s)<title>.*?</title>\s*`)
+	htmlTagRegex = regexp.MustCompile(`<html[^>]*>`)
+)
+
+// Meta is the per-page head content
+type Meta struct {
+	Lang        string
+	SiteName    string
+	Title       string
+	Description string
+	URL         string // canonical, absolute
+	Image       string // absolute; optional
+	OGType      string // "website" or "book"
+	JSONLD      []any  // schema.org objects, one script tag each
+}
+
+// Owner is the public owner info shown on the pages
+type Owner struct {
+	Name    string
+	Tagline string
+}
+
+// HomePage is the data of "/"
+type HomePage struct {
+	Meta  Meta
+	Owner Owner
+	Books []model.BookResponse
+}
+
+// BookPage is the data of "/books/:id"
+type BookPage struct {
+	Meta  Meta
+	Owner Owner
+	Book  model.BookResponse
+	Blurb string
+	Image string
+}
+
+// Renderer splices rendered pages into the app shell (the built index.html)
+type Renderer struct {
+	before string // shell up to </head>
+	middle string // </head> up to the root element
+	after  string // after the root element (the app scripts)
+	tmpl   *template.Template
+}
+
+// NewRenderer prepares the shell. It must contain </head> and an empty <div id="root"></div>.
+func NewRenderer(indexHTML []byte) (*Renderer, error) {
+	shell := titleRegex.ReplaceAllString(string(indexHTML), "")
+
+	headEnd := strings.Index(shell, "</head>")
+	rootStart := strings.Index(shell, rootElement)
+	if headEnd < 0 || rootStart < headEnd {
+		return nil, errors.New("index.html has no </head> followed by an empty root element")
+	}
+
+	tmpl, err := template.ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page templates: %w", err)
+	}
+
+	return &Renderer{
+		before: shell[:headEnd],
+		middle: shell[headEnd:rootStart],
+		after:  shell[rootStart+len(rootElement):],
+		tmpl:   tmpl,
+	}, nil
+}
+
+// RenderHome writes the home page
+func (r *Renderer) RenderHome(w io.Writer, page HomePage) error {
+	return r.render(w, page.Meta, "home", page)
+}
+
+// RenderBook writes a book page
+func (r *Renderer) RenderBook(w io.Writer, page BookPage) error {
+	return r.render(w, page.Meta, "book", page)
+}
+
+func (r *Renderer) render(w io.Writer, meta Meta, body string, data any) error {
+	var head, content bytes.Buffer
+	if err := r.tmpl.ExecuteTemplate(&head, "head", meta); err != nil {
+		return fmt.Errorf("failed to render head: %w", err)
+	}
+	if err := r.tmpl.ExecuteTemplate(&content, body, data); err != nil {
+		return fmt.Errorf("failed to render %s page: %w", body, err)
+	}
+
+	before := r.before
+	if meta.Lang != "" {
+		before = htmlTagRegex.ReplaceAllLiteralString(before, `<html lang="`+template.HTMLEscapeString(meta.Lang)+`">`)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(strings.TrimRight(before, " \t\n"))
+	out.WriteString("\n    ")
+	out.Write(bytes.TrimSpace(head.Bytes()))
+	out.WriteString("\n  ")
+	out.WriteString(r.middle)
+	out.WriteString(`<div id="root">`)
+	out.Write(content.Bytes())
+	out.WriteString(`</div>`)
+	out.WriteString(r.after)
+	_, err := w.Write(out.Bytes())
+	return err
+}
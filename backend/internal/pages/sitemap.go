@@ -0,0 +1,26 @@
+package pages
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// SitemapURL is one <url> of sitemap.xml
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"` // YYYY-MM-DD
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// Sitemap renders a sitemaps.org urlset
+func Sitemap(urls []SitemapURL) ([]byte, error) {
+	data, err := xml.MarshalIndent(urlSet{URLs: urls}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
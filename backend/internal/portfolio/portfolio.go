@@ -2,6 +2,7 @@ package portfolio
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 )
@@ -61,3 +62,11 @@ func LoadPortfolio(path string) (*Portfolio, error) {
 
 	return &portfolio, nil
 }
+
+// Validate checks the fields the owner endpoint and agent depend on
+func (p *Portfolio) Validate() error {
+	if p.About.Name == "" {
+		return errors.New("about.name is empty")
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+// Package related finds books similar to a given book from the owner's notes,
+// tags and authors, so recommendations come from the shelf rather than the
+// model's guesswork. It backs GET /api/books/:id/related and find_similar_books.
+package related
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/search"
+)
+
+// DefaultLimit is the number of related books returned when no limit is given
+const DefaultLimit = 5
+
+// MaxLimit is how many related books are kept per book
+const MaxLimit = 20
+
+// Scoring: notes similarity is a TF-IDF cosine (0..1); shared labels and authors add to it
+const (
+	tagWeight   = 0.3  // per shared tag
+	genreWeight = 0.05 // per shared genre
+	authorBoost = 0.5  // at least one author in common
+	// minScore drops pairs that only share genres or a common word or two
+	minScore = 0.1
+	// reasonTerms is how many shared note terms a "both discuss" reason names
+	reasonTerms = 2
+	// minTermWeight is the smallest contribution of a term worth naming in a reason
+	minTermWeight = 0.02
+)
+
+// Match is a book related to another, with a score and short reasons.
+// Reasons only name public fields (authors, tags, subjects, genres); the terms
+// shared by the owner's notes, which may be private, are kept in NoteReasons.
+type Match struct {
+	Book        model.Book
+	Score       float64
+	Reasons     []string // e.g. "both by Robert C. Martin", "both about Software architecture"
+	NoteReasons []string // e.g. "both discuss replication"; not for public responses
+}
+
+// Index holds the related books of every book in a snapshot, computed up front.
+// Matches refer to books by position so the snapshot is not copied per pair.
+type Index struct {
+	books   []model.Book
+	related map[string][]match // book ID -> at most MaxLimit matches, best first
+}
+
+// match is a Match with the book as its position in Index.books
+type match struct {
+	Match
+	doc int
+}
+
+// profile is what a book is compared on
+type profile struct {
+	terms    map[string]float64 // normalized TF-IDF weights of the notes
+	tags     map[string]string  // lowercased tag -> tag as written
+	subjects map[string]string  // lowercased subject -> subject as written
+	genres   map[string]bool
+	authors  map[string]string // author key -> name as written
+}
+
+// NewIndex compares every pair of books. The books slice must not be modified afterwards.
+func NewIndex(books []model.Book) *Index {
+	profiles := make([]profile, len(books))
+	docFreq := make(map[string]int)
+	counts := make([]map[string]int, len(books))
+	for i := range books {
+		counts[i] = make(map[string]int)
+		for _, term := range search.Tokenize(notesText(&books[i])) {
+			if isContentTerm(term) {
+				counts[i][term]++
+			}
+		}
+		for term := range counts[i] {
+			docFreq[term]++
+		}
+	}
+
+	n := float64(len(books))
+	for i := range books {
+		p := profile{
+			terms:    make(map[string]float64),
+			tags:     make(map[string]string),
+			subjects: make(map[string]string),
+			genres:   make(map[string]bool),
+			authors:  make(map[string]string),
+		}
+		var norm float64
+		for term, tf := range counts[i] {
+			w := (1 + math.Log(float64(tf))) * math.Log(n/float64(docFreq[term]))
+			if w > 0 {
+				p.terms[term] = w
+				norm += w * w
+			}
+		}
+		norm = math.Sqrt(norm)
+		for term := range p.terms {
+			p.terms[term] /= norm
+		}
+		for _, tag := range books[i].Tags {
+			if key := strings.ToLower(strings.TrimSpace(tag)); key != "" {
+				p.tags[key] = strings.TrimSpace(tag)
+			}
+		}
+		for _, subject := range books[i].Subjects {
+			if key := strings.ToLower(strings.TrimSpace(subject)); key != "" {
+				p.subjects[key] = strings.TrimSpace(subject)
+			}
+		}
+		for _, genre := range books[i].Genres {
+			p.genres[genre] = true
+		}
+		for _, name := range books[i].Authors() {
+			if key := model.AuthorKey(name); key != "" {
+				p.authors[key] = model.NormalizeAuthor(name)
+			}
+		}
+		profiles[i] = p
+	}
+
+	ix := &Index{books: books, related: make(map[string][]match, len(books))}
+	for i := range books {
+		var matches []match
+		for j := range books {
+			if i == j {
+				continue
+			}
+			if m, ok := compare(&profiles[i], &profiles[j]); ok {
+				matches = append(matches, match{Match: m, doc: j})
+			}
+		}
+		// Ties keep shelf order so results are deterministic
+		sort.SliceStable(matches, func(a, b int) bool { return matches[a].Score > matches[b].Score })
+		if len(matches) > MaxLimit {
+			matches = slices.Clip(matches[:MaxLimit])
+		}
+		ix.related[books[i].ID] = matches
+	}
+	return ix
+}
+
+// Related returns up to MaxLimit books related to the book with the given ID,
+// best first (nil for unknown IDs)
+func (ix *Index) Related(id string) []Match {
+	matches := ix.related[id]
+	if matches == nil {
+		return nil
+	}
+	result := make([]Match, len(matches))
+	for i, m := range matches {
+		result[i] = m.Match
+		result[i].Book = ix.books[m.doc]
+	}
+	return result
+}
+
+// compare scores b against a and explains the score
+func compare(a, b *profile) (Match, bool) {
+	var m Match
+
+	var sharedAuthors []string
+	for key, name := range a.authors {
+		if _, ok := b.authors[key]; ok {
+			sharedAuthors = append(sharedAuthors, name)
+		}
+	}
+	if len(sharedAuthors) > 0 {
+		sort.Strings(sharedAuthors)
+		m.Score += authorBoost
+		m.Reasons = append(m.Reasons, "both by "+joinAnd(sharedAuthors))
+	}
+
+	var sharedTags []string
+	for key, tag := range a.tags {
+		if _, ok := b.tags[key]; ok {
+			sharedTags = append(sharedTags, tag)
+		}
+	}
+	if len(sharedTags) > 0 {
+		sort.Strings(sharedTags)
+		m.Score += tagWeight * float64(len(sharedTags))
+		m.Reasons = append(m.Reasons, "both tagged "+joinAnd(sharedTags))
+	}
+
+	// Subjects already count through the notes similarity, so they only explain it
+	var sharedSubjects []string
+	for key, subject := range a.subjects {
+		if _, ok := b.subjects[key]; ok {
+			sharedSubjects = append(sharedSubjects, subject)
+		}
+	}
+	if len(sharedSubjects) > 0 {
+		sort.Strings(sharedSubjects)
+		m.Reasons = append(m.Reasons, "both about "+joinAnd(sharedSubjects))
+	}
+
+	type shared struct {
+		term   string
+		weight float64
+	}
+	var terms []shared
+	var cosine float64
+	for term, w := range a.terms {
+		if v, ok := b.terms[term]; ok {
+			cosine += w * v
+			terms = append(terms, shared{term, w * v})
+		}
+	}
+	m.Score += cosine
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].weight != terms[j].weight {
+			return terms[i].weight > terms[j].weight
+		}
+		return terms[i].term < terms[j].term
+	})
+	var named []string
+	for _, t := range terms {
+		if len(named) == reasonTerms || t.weight < minTermWeight {
+			break
+		}
+		named = append(named, t.term)
+	}
+	if len(named) > 0 {
+		m.NoteReasons = append(m.NoteReasons, "both discuss "+joinAnd(named))
+	}
+
+	var sharedGenres []string
+	for genre := range a.genres {
+		if b.genres[genre] {
+			sharedGenres = append(sharedGenres, model.GenreLabel(genre, "en"))
+		}
+	}
+	if len(sharedGenres) > 0 {
+		sort.Strings(sharedGenres)
+		m.Score += genreWeight * float64(len(sharedGenres))
+		m.Reasons = append(m.Reasons, "both in "+joinAnd(sharedGenres))
+	}
+
+	m.Score = math.Round(m.Score*100) / 100
+	return m, m.Score >= minScore
+}
+
+// notesText is the owner's own text about the book plus publisher subjects
+func notesText(b *model.Book) string {
+	parts := []string{b.PrivateNotes}
+	for _, h := range b.Highlights {
+		parts = append(parts, h.Text, h.Note)
+	}
+	parts = append(parts, b.Subjects...)
+	return strings.Join(parts, "\n")
+}
+
+// isContentTerm drops tokens that say nothing about a book's topic: stop words,
+// numbers, single letters and kana-only bigrams (mostly particles like "のは")
+func isContentTerm(term string) bool {
+	runes := []rune(term)
+	if len(runes) < 2 || stopWords[term] {
+		return false
+	}
+	letters, hiragana := false, true
+	for _, r := range runes {
+		if unicode.IsLetter(r) {
+			letters = true
+		}
+		if !unicode.Is(unicode.Hiragana, r) && r != 'ー' {
+			hiragana = false
+		}
+	}
+	return letters && !hiragana
+}
+
+// joinAnd joins words as "a", "a and b" or "a, b and c"
+func joinAnd(words []string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// stopWords are common English words that Tokenize keeps but that never describe a topic
+var stopWords = map[string]bool{
+	"about": true, "after": true, "all": true, "also": true, "an": true, "and": true,
+	"any": true, "are": true, "as": true, "at": true, "be": true, "because": true, "been": true,
+	"book": true, "but": true, "by": true, "can": true, "could": true, "did": true, "do": true,
+	"does": true, "each": true, "even": true, "every": true, "for": true, "from": true, "get": true,
+	"had": true, "has": true, "have": true, "how": true, "if": true, "in": true, "into": true,
+	"is": true, "it": true, "its": true, "just": true, "like": true, "made": true, "make": true,
+	"many": true, "more": true, "most": true, "much": true, "my": true, "no": true, "not": true,
+	"of": true, "on": true, "one": true, "only": true, "or": true, "other": true, "our": true,
+	"out": true, "over": true, "really": true, "so": true, "some": true, "such": true, "than": true,
+	"that": true, "the": true, "their": true, "them": true, "then": true, "there": true,
+	"these": true, "they": true, "this": true, "those": true, "through": true, "to": true,
+	"too": true, "up": true, "very": true, "was": true, "way": true, "we": true, "well": true,
+	"were": true, "what": true, "when": true, "which": true, "while": true, "who": true,
+	"why": true, "will": true, "with": true, "would": true, "you": true, "your": true,
+}
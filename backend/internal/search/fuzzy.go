@@ -0,0 +1,187 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyMinScore is the similarity (0..1) a book needs to be returned by the fuzzy fallback
+const fuzzyMinScore = 0.6
+
+// fuzzyTerm is a word of a book's title or author kept for typo-tolerant matching
+type fuzzyTerm struct {
+	text  []rune
+	field string
+}
+
+// fuzzyTerms collects the words of the title and author, whole (not bigrams),
+// plus the romanization of kana words and of the whole title
+func fuzzyTerms(title, author string) []fuzzyTerm {
+	var terms []fuzzyTerm
+	add := func(text, field string) {
+		if text != "" {
+			terms = append(terms, fuzzyTerm{text: []rune(text), field: field})
+		}
+	}
+
+	for _, f := range []struct{ text, name string }{{title, "title"}, {author, "author"}} {
+		for _, word := range words(f.text) {
+			add(word, f.name)
+			add(Romanize(word), f.name)
+		}
+	}
+
+	compact := strings.Join(words(title), "")
+	add(compact, "title")
+	add(Romanize(compact), "title")
+	return terms
+}
+
+// words splits normalized text at spaces and punctuation, keeping
+// Japanese runs whole (unlike Tokenize, which turns them into bigrams)
+func words(text string) []string {
+	return strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !isCJK(r)
+	})
+}
+
+// fuzzySearch scores every book by how closely the query words match title and
+// author words within a small edit distance. It is the fallback when BM25 finds nothing.
+func (ix *Index) fuzzySearch(query string, limit int) []Result {
+	queryWords := words(query)
+	if len(queryWords) == 0 {
+		return nil
+	}
+	// A query typed without spaces ("pragmaticprogramer") is also compared as a whole
+	compactQuery := []rune(strings.Join(queryWords, ""))
+
+	type scored struct {
+		doc   int
+		score float64
+		field string
+	}
+	var hits []scored
+
+	for doc, terms := range ix.fuzzy {
+		total := 0.0
+		fieldScores := make(map[string]float64)
+		for _, word := range queryWords {
+			best, field := bestSimilarity([]rune(word), terms)
+			total += best
+			fieldScores[field] += best
+		}
+		score := total / float64(len(queryWords))
+		bestField := maxField(fieldScores)
+
+		if whole, field := bestSimilarity(compactQuery, terms); whole > score {
+			score, bestField = whole, field
+		}
+		if score >= fuzzyMinScore {
+			hits = append(hits, scored{doc: doc, score: score, field: bestField})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].doc < hits[j].doc
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	results := make([]Result, len(hits))
+	for i, h := range hits {
+		results[i] = Result{Book: ix.books[h.doc], Score: h.score, Field: h.field, Fuzzy: true}
+	}
+	return results
+}
+
+// bestSimilarity returns the highest similarity of word to any term and that term's field
+func bestSimilarity(word []rune, terms []fuzzyTerm) (float64, string) {
+	best, field := 0.0, ""
+	for _, term := range terms {
+		if sim := similarity(word, term.text); sim > best {
+			best, field = sim, term.field
+		}
+	}
+	return best, field
+}
+
+// Similarity compares two normalized strings with the same typo tolerance as the
+// fuzzy search: 1 for identical strings, 0 when they are too far apart
+func Similarity(a, b string) float64 {
+	return similarity([]rune(a), []rune(b))
+}
+
+// similarity is 1 - distance/length when the edit distance is within tolerance, else 0.
+// Short words allow one edit, longer words two or three.
+func similarity(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 0
+	}
+	allowed := 1
+	switch {
+	case len(a) < 3:
+		allowed = 0
+	case len(a) > 8:
+		allowed = 3
+	case len(a) > 4:
+		allowed = 2
+	}
+	if abs(len(a)-len(b)) > allowed {
+		return 0
+	}
+
+	d := editDistance(a, b)
+	if d > allowed {
+		return 0
+	}
+	return 1 - float64(d)/float64(longest)
+}
+
+// editDistance is the optimal string alignment distance (Levenshtein plus adjacent swaps)
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func maxField(scores map[string]float64) string {
+	best, field := -1.0, ""
+	for f, s := range scores {
+		if s > best || (s == best && f < field) {
+			best, field = s, f
+		}
+	}
+	return field
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,208 @@
+// Package search provides ranked full-text search over the bookshelf.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// DefaultLimit caps how many books a search returns
+const DefaultLimit = 10
+
+// BM25 parameters (the usual defaults)
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// field is a searchable part of a book with its ranking boost
+type field struct {
+	name  string
+	boost float64
+	text  func(book *model.Book) string
+}
+
+// fields are weighted so that a title or author hit outranks a passing mention in the notes
+var fields = []field{
+	{name: "title", boost: 3.0, text: func(b *model.Book) string { return b.Title }},
+	{name: "author", boost: 2.0, text: func(b *model.Book) string { return b.Author }},
+	{name: "notes", boost: 1.0, text: notesText},
+}
+
+// notesText is the owner's own text about the book: notes plus highlights
+func notesText(b *model.Book) string {
+	if len(b.Highlights) == 0 {
+		return b.PrivateNotes
+	}
+	parts := []string{b.PrivateNotes}
+	for _, h := range b.Highlights {
+		parts = append(parts, h.Text, h.Note)
+		parts = append(parts, h.Tags...)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// fieldIndex holds the postings and lengths of one field
+type fieldIndex struct {
+	postings map[string]map[int]int // term -> doc -> term frequency
+	lengths  []int
+	avgLen   float64
+}
+
+// Index is an immutable inverted index over a snapshot of books
+type Index struct {
+	books   []model.Book
+	fields  []fieldIndex
+	docFreq map[string]int // number of books containing the term in any field
+	fuzzy   [][]fuzzyTerm  // per book, for the typo-tolerant fallback
+}
+
+// Result is a book with its relevance score.
+// Score is a BM25 score for exact matches and a 0..1 similarity when Fuzzy is set.
+type Result struct {
+	Book  model.Book
+	Score float64
+	Field string // field that contributed most to the match ("title", "author" or "notes")
+	Fuzzy bool
+}
+
+// NewIndex builds the index. The books slice must not be modified afterwards.
+func NewIndex(books []model.Book) *Index {
+	ix := &Index{
+		books:   books,
+		fields:  make([]fieldIndex, len(fields)),
+		docFreq: make(map[string]int),
+		fuzzy:   make([][]fuzzyTerm, len(books)),
+	}
+
+	for f := range fields {
+		ix.fields[f] = fieldIndex{
+			postings: make(map[string]map[int]int),
+			lengths:  make([]int, len(books)),
+		}
+	}
+
+	for doc := range books {
+		ix.fuzzy[doc] = fuzzyTerms(books[doc].Title, books[doc].Author)
+
+		seen := make(map[string]bool)
+		for f, fd := range fields {
+			tokens := Tokenize(fd.text(&books[doc]))
+			fi := &ix.fields[f]
+			fi.lengths[doc] = len(tokens)
+			for _, term := range tokens {
+				if fi.postings[term] == nil {
+					fi.postings[term] = make(map[int]int)
+				}
+				fi.postings[term][doc]++
+				if !seen[term] {
+					seen[term] = true
+					ix.docFreq[term]++
+				}
+			}
+		}
+	}
+
+	for f := range ix.fields {
+		fi := &ix.fields[f]
+		total := 0
+		for _, n := range fi.lengths {
+			total += n
+		}
+		if len(books) > 0 {
+			fi.avgLen = float64(total) / float64(len(books))
+		}
+	}
+
+	return ix
+}
+
+// Search ranks books against the query with BM25 (field-boosted) and returns
+// at most limit results (all of them when limit is 0), best first. Any query term may match.
+// When nothing matches exactly, it falls back to fuzzy matching on title and author.
+func (ix *Index) Search(query string, limit int) []Result {
+	if len(ix.books) == 0 {
+		return nil
+	}
+	if results := ix.exactSearch(query, limit); len(results) > 0 {
+		return results
+	}
+	return ix.fuzzySearch(query, limit)
+}
+
+func (ix *Index) exactSearch(query string, limit int) []Result {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	fieldScores := make(map[int][]float64)
+	n := float64(len(ix.books))
+	for _, term := range terms {
+		df := float64(ix.docFreq[term])
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for f, fd := range fields {
+			fi := &ix.fields[f]
+			for doc, tf := range fi.postings[term] {
+				norm := 1 - bm25B
+				if fi.avgLen > 0 {
+					norm += bm25B * float64(fi.lengths[doc]) / fi.avgLen
+				}
+				score := fd.boost * idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+				scores[doc] += score
+				if fieldScores[doc] == nil {
+					fieldScores[doc] = make([]float64, len(fields))
+				}
+				fieldScores[doc][f] += score
+			}
+		}
+	}
+
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	// Ties keep shelf order so results are deterministic
+	sort.Slice(docs, func(i, j int) bool {
+		if scores[docs[i]] != scores[docs[j]] {
+			return scores[docs[i]] > scores[docs[j]]
+		}
+		return docs[i] < docs[j]
+	})
+
+	results := make([]Result, len(docs))
+	for i, doc := range docs {
+		best := 0
+		for f, score := range fieldScores[doc] {
+			if score > fieldScores[doc][best] {
+				best = f
+			}
+		}
+		results[i] = Result{Book: ix.books[doc], Score: scores[doc], Field: fields[best].name}
+	}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize folds the differences visitors shouldn't have to care about:
+// NFKC turns full-width Latin and half-width katakana into their usual forms,
+// letters are lowercased, and katakana is folded to hiragana so "コード" and "こーど" match.
+func Normalize(s string) string {
+	s = strings.ToLower(norm.NFKC.String(s))
+	return strings.Map(func(r rune) rune {
+		// Katakana ァ(U+30A1)..ヶ(U+30F6) sit 0x60 above their hiragana counterparts
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
+
+// romaji maps hiragana to Hepburn romanization
+var romaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ゔ": "vu",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+// Romanize converts normalized kana to romaji so "りーだぶる" can match "riidaburu".
+// It returns "" when s contains anything other than kana (kanji need a dictionary).
+func Romanize(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	doubleNext := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case 'っ':
+			doubleNext = true
+			continue
+		case 'ー':
+			// Long vowel mark repeats the previous vowel
+			out := b.String()
+			if out == "" {
+				return ""
+			}
+			b.WriteByte(out[len(out)-1])
+			continue
+		}
+
+		var syllable string
+		if i+1 < len(runes) {
+			if pair, ok := romaji[string(runes[i:i+2])]; ok {
+				syllable = pair
+				i++
+			}
+		}
+		if syllable == "" {
+			single, ok := romaji[string(r)]
+			if !ok {
+				return ""
+			}
+			syllable = single
+		}
+
+		if doubleNext {
+			b.WriteByte(syllable[0])
+			doubleNext = false
+		}
+		b.WriteString(syllable)
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+)
+
+// CacheFile is the name of the vector cache kept next to a books.json (vectors
+// only, and not written at all when the notes are encrypted)
+const CacheFile = "books.vectors.json"
+
+// NewEmbedder selects the embedder from EMBEDDER: "hash" (default, offline),
+// "gemini" (Gemini embedding API) or "none" (semantic search disabled)
+func NewEmbedder(ctx context.Context) (deps.Embedder, error) {
+	switch name := os.Getenv("EMBEDDER"); name {
+	case "", "hash":
+		return NewHashEmbedder(DefaultHashDimensions), nil
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("EMBEDDER=gemini requires GEMINI_API_KEY")
+		}
+		return NewGeminiEmbedder(ctx, apiKey, DefaultEmbeddingModel)
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDER %q (expected hash, gemini or none)", name)
+	}
+}
@@ -0,0 +1,63 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// DefaultEmbeddingModel is the Gemini model used by GeminiEmbedder
+const DefaultEmbeddingModel = "gemini-embedding-001"
+
+// embedBatchSize is the maximum number of texts sent in one EmbedContent call
+const embedBatchSize = 100
+
+// GeminiEmbedder implements deps.Embedder using the Gemini embedding API
+type GeminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiEmbedder creates a GeminiEmbedder with its own API client
+func NewGeminiEmbedder(ctx context.Context, apiKey, model string) (*GeminiEmbedder, error) {
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey: apiKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	return &GeminiEmbedder{client: client, model: model}, nil
+}
+
+// Name identifies the embedding model for the vector cache
+func (e *GeminiEmbedder) Name() string {
+	return "gemini:" + e.model
+}
+
+// Embed returns one vector per text
+func (e *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	config := &genai.EmbedContentConfig{TaskType: "SEMANTIC_SIMILARITY"}
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		end := min(start+embedBatchSize, len(texts))
+
+		contents := make([]*genai.Content, 0, end-start)
+		for _, text := range texts[start:end] {
+			contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
+		}
+
+		resp, err := e.client.Models.EmbedContent(ctx, e.model, contents, config)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", end-start, len(resp.Embeddings))
+		}
+		for _, embedding := range resp.Embeddings {
+			vectors = append(vectors, embedding.Values)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,81 @@
+// Package semantic provides vector search over the owner's notes.
+package semantic
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"talking-bookshelf/backend/internal/search"
+)
+
+// DefaultHashDimensions is the vector size of HashEmbedder
+const DefaultHashDimensions = 512
+
+// HashEmbedder is a deterministic offline embedder.
+// Each text becomes a bag of hashed features (the search tokens, which are
+// words and Japanese bigrams, plus character trigrams of words) folded into
+// a fixed-size vector. It captures shared vocabulary rather than meaning,
+// but needs no network and always gives the same vectors for the same text.
+type HashEmbedder struct {
+	dims int
+}
+
+// NewHashEmbedder creates a HashEmbedder with the given vector size
+func NewHashEmbedder(dims int) *HashEmbedder {
+	return &HashEmbedder{dims: dims}
+}
+
+// Name identifies the feature set and size; bump the version when features change
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-ngram-v1-%d", e.dims)
+}
+
+// Embed returns one L2-normalized vector per text
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	vec := make([]float64, e.dims)
+	add := func(feature string, weight float64) {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		// The top bit picks the sign so unrelated features cancel out instead of piling up
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		vec[sum%uint32(e.dims)] += weight
+	}
+
+	for _, token := range search.Tokenize(text) {
+		add("t:"+token, 1)
+		runes := []rune(token)
+		if len(runes) > 3 {
+			for i := 0; i+3 <= len(runes); i++ {
+				add("c:"+string(runes[i:i+3]), 0.5)
+			}
+		}
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	out := make([]float32, e.dims)
+	if norm == 0 {
+		return out
+	}
+	for i, v := range vec {
+		out[i] = float32(v / norm)
+	}
+	return out
+}
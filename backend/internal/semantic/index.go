@@ -0,0 +1,338 @@
+package semantic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/model"
+)
+
+// maxChunkRunes is the target size of a notes chunk
+const maxChunkRunes = 300
+
+// chunk is an embedded passage of one book's notes. Only the vector is cached;
+// the text is taken from the (decrypted) books on Update, by position in Chunks,
+// so the cache file never holds the notes themselves.
+type chunk struct {
+	Text   string    `json:"-"`
+	Vector []float32 `json:"vector"`
+}
+
+// bookEntry holds the chunks of one book and the hash of the text they came from
+type bookEntry struct {
+	Hash   string  `json:"hash"`
+	Chunks []chunk `json:"chunks"`
+}
+
+// cacheVersion is bumped when the cache layout changes; version 1 also stored the chunk texts
+const cacheVersion = 2
+
+// cacheFile is the on-disk vector cache
+type cacheFile struct {
+	Version  int                  `json:"version"`
+	Embedder string               `json:"embedder"`
+	Books    map[string]bookEntry `json:"books"`
+}
+
+// Index is a vector index over notes chunks, backed by a cache file so that
+// only books whose notes changed are embedded again
+type Index struct {
+	embedder  deps.Embedder
+	cachePath string
+
+	mu    sync.RWMutex
+	books map[string]bookEntry
+	order []string // book IDs in shelf order, for deterministic results
+
+	// encryptedNotes is set when the notes are encrypted at rest; the cache is then not written
+	encryptedNotes bool
+}
+
+// NewIndex creates an empty index; call Update to fill it
+func NewIndex(embedder deps.Embedder, cachePath string) *Index {
+	return &Index{embedder: embedder, cachePath: cachePath, books: make(map[string]bookEntry)}
+}
+
+// SetEncryptedNotes tells the index whether the notes are encrypted at rest.
+// Vectors are derived from the plaintext, so for encrypted notes the cache file
+// is not written and one left from before is removed. Call it before Update.
+func (ix *Index) SetEncryptedNotes(encrypted bool) {
+	ix.encryptedNotes = encrypted
+}
+
+// Update syncs the index with books (callers serialize updates). Cached vectors are reused for books whose
+// notes are unchanged; the rest are embedded and the cache file is rewritten.
+func (ix *Index) Update(ctx context.Context, books []model.Book) error {
+	ix.mu.RLock()
+	current := ix.books
+	ix.mu.RUnlock()
+	outdated := false
+	if len(current) == 0 {
+		current, outdated = ix.loadCache()
+	}
+
+	next := make(map[string]bookEntry, len(books))
+	order := make([]string, 0, len(books))
+	var pendingIDs []string
+	var pendingTexts [][]string
+	reused := 0
+
+	for _, book := range books {
+		texts := Chunks(book)
+		hash := hashChunks(texts)
+		order = append(order, book.ID)
+
+		if entry, ok := current[book.ID]; ok && entry.Hash == hash && len(entry.Chunks) == len(texts) {
+			next[book.ID] = withTexts(entry, texts)
+			reused++
+			continue
+		}
+		if len(texts) == 0 {
+			next[book.ID] = bookEntry{Hash: hash}
+			continue
+		}
+		pendingIDs = append(pendingIDs, book.ID)
+		pendingTexts = append(pendingTexts, texts)
+	}
+
+	for i, id := range pendingIDs {
+		vectors, err := ix.embedder.Embed(ctx, pendingTexts[i])
+		if err != nil {
+			return fmt.Errorf("failed to embed notes of %s: %w", id, err)
+		}
+		if len(vectors) != len(pendingTexts[i]) {
+			return fmt.Errorf("embedder returned %d vectors for %d chunks of %s", len(vectors), len(pendingTexts[i]), id)
+		}
+		entry := bookEntry{Hash: hashChunks(pendingTexts[i])}
+		for j, text := range pendingTexts[i] {
+			entry.Chunks = append(entry.Chunks, chunk{Text: text, Vector: vectors[j]})
+		}
+		next[id] = entry
+	}
+
+	ix.mu.Lock()
+	ix.books = next
+	ix.order = order
+	ix.mu.Unlock()
+
+	log.Printf("[SEMANTIC] Index updated: books=%d embedded=%d reused=%d embedder=%s",
+		len(books), len(pendingIDs), reused, ix.embedder.Name())
+
+	if ix.encryptedNotes {
+		ix.removeCache()
+	} else if len(pendingIDs) > 0 || len(next) != len(current) || outdated {
+		if err := ix.saveCache(next); err != nil {
+			log.Printf("[SEMANTIC] Failed to write vector cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// withTexts returns a copy of entry with the chunk texts filled in
+func withTexts(entry bookEntry, texts []string) bookEntry {
+	chunks := make([]chunk, len(entry.Chunks))
+	for i, c := range entry.Chunks {
+		chunks[i] = chunk{Text: texts[i], Vector: c.Vector}
+	}
+	return bookEntry{Hash: entry.Hash, Chunks: chunks}
+}
+
+// SearchNotes returns the best matching passage of each book, most similar first
+func (ix *Index) SearchNotes(ctx context.Context, query string, limit int) ([]deps.NoteMatch, error) {
+	vectors, err := ix.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, errors.New("embedder returned no vector for the query")
+	}
+	queryVec := vectors[0]
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var matches []deps.NoteMatch
+	for _, id := range ix.order {
+		best := deps.NoteMatch{BookID: id, Score: -1}
+		for _, c := range ix.books[id].Chunks {
+			if score := cosine(queryVec, c.Vector); score > best.Score {
+				best.Score, best.Text = score, c.Text
+			}
+		}
+		if best.Score > 0 {
+			matches = append(matches, best)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Chunks splits a book's notes and highlights into passages of about maxChunkRunes.
+// The leading title line that notes conventionally start with is skipped.
+func Chunks(book model.Book) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+		current.Reset()
+	}
+
+	for i, line := range strings.Split(book.PrivateNotes, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (i == 0 && line == book.Title) {
+			continue
+		}
+		for _, sentence := range splitSentences(line) {
+			if current.Len() > 0 && len([]rune(current.String()))+len([]rune(sentence)) > maxChunkRunes {
+				flush()
+			}
+			if current.Len() > 0 {
+				current.WriteString(" ")
+			}
+			current.WriteString(sentence)
+		}
+	}
+	flush()
+
+	for _, h := range book.Highlights {
+		text := strings.TrimSpace(strings.TrimSpace(h.Text) + "\n" + strings.TrimSpace(h.Note))
+		if text != "" {
+			chunks = append(chunks, text)
+		}
+	}
+	return chunks
+}
+
+// splitSentences splits after Japanese and English sentence terminators
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		switch r {
+		case '。', '！', '？', '.', '!', '?':
+			if i+1 == len(runes) || r >= 0x3000 || runes[i+1] == ' ' {
+				if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+					sentences = append(sentences, s)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+func hashChunks(texts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(texts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// loadCache reads the cache file, ignoring it if it was built by another embedder.
+// outdated reports an older layout that should be rewritten even if nothing changed.
+func (ix *Index) loadCache() (books map[string]bookEntry, outdated bool) {
+	if ix.cachePath == "" || ix.encryptedNotes {
+		return nil, false
+	}
+	data, err := os.ReadFile(ix.cachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[SEMANTIC] Failed to read vector cache: %v", err)
+		}
+		return nil, false
+	}
+
+	var cache cacheFile
+	if err := json.Unmarshal(data, &cache); err != nil {
+		log.Printf("[SEMANTIC] Ignoring unreadable vector cache: %v", err)
+		return nil, false
+	}
+	if cache.Embedder != ix.embedder.Name() {
+		log.Printf("[SEMANTIC] Vector cache was built with %s, rebuilding with %s", cache.Embedder, ix.embedder.Name())
+		return nil, false
+	}
+	return cache.Books, cache.Version != cacheVersion
+}
+
+// saveCache writes the cache atomically (temp file + rename)
+func (ix *Index) saveCache(books map[string]bookEntry) error {
+	if ix.cachePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(cacheFile{Version: cacheVersion, Embedder: ix.embedder.Name(), Books: books})
+	if err != nil {
+		return fmt.Errorf("failed to encode vector cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ix.cachePath), ".vectors-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vector cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vector cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write vector cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), ix.cachePath); err != nil {
+		return fmt.Errorf("failed to replace vector cache: %w", err)
+	}
+	return nil
+}
+
+// removeCache deletes the cache file, e.g. one written before the notes were encrypted
+func (ix *Index) removeCache() {
+	if ix.cachePath == "" {
+		return
+	}
+	if err := os.Remove(ix.cachePath); err == nil {
+		log.Printf("[SEMANTIC] Removed vector cache %s (notes are encrypted)", ix.cachePath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("[SEMANTIC] Failed to remove vector cache: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+// Package shelf loads the additional bookshelves served next to the default one.
+//
+// Each shelf lives in its own directory, data/shelves/<slug>/, with the same
+// files as the default shelf:
+//
+//	books.json      the owner's books (required)
+//	portfolio.json  the owner's profile (required)
+//	persona.md      how the bookshelf should talk (optional)
+package shelf
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"talking-bookshelf/backend/internal/agent/deps"
+	"talking-bookshelf/backend/internal/portfolio"
+	"talking-bookshelf/backend/internal/store"
+)
+
+const (
+	// DefaultSlug names the default shelf (data/books.json and data/portfolio.json)
+	DefaultSlug = "default"
+	// BooksFile, PortfolioFile and PersonaFile are the files of a shelf directory
+	BooksFile     = "books.json"
+	PortfolioFile = "portfolio.json"
+	PersonaFile   = "persona.md"
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// Shelf bundles one owner's books, portfolio and persona
+type Shelf struct {
+	Slug      string
+	Dir       string
+	Books     deps.BookRepository
+	Portfolio *portfolio.Portfolio
+	Persona   string
+	// EncryptedNotes is set when books.json holds encrypted notes
+	EncryptedNotes bool
+}
+
+// ValidSlug reports whether slug can name a shelf (lowercase letters, digits and hyphens)
+func ValidSlug(slug string) bool {
+	return slugRegex.MatchString(slug)
+}
+
+// Load reads and validates the shelf in dir; the slug is the directory name.
+// Encrypted notes are decrypted according to notes.
+func Load(dir string, notes store.NotesPolicy) (*Shelf, error) {
+	slug := filepath.Base(dir)
+	if !ValidSlug(slug) {
+		return nil, fmt.Errorf("invalid shelf slug %q (use lowercase letters, digits and hyphens)", slug)
+	}
+	if slug == DefaultSlug {
+		return nil, fmt.Errorf("shelf slug %q is reserved for data/books.json", DefaultSlug)
+	}
+
+	booksPath := filepath.Join(dir, BooksFile)
+	books, err := store.LoadBooksJSON(booksPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.ValidateBooks(books); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", booksPath, err)
+	}
+	encrypted, err := notes.Open(books, booksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolioPath := filepath.Join(dir, PortfolioFile)
+	p, err := portfolio.LoadPortfolio(portfolioPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", portfolioPath, err)
+	}
+
+	persona, err := os.ReadFile(filepath.Join(dir, PersonaFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read persona: %w", err)
+	}
+
+	return &Shelf{
+		Slug:      slug,
+		Dir:       dir,
+		Books:     store.NewInMemoryBookRepository(books),
+		Portfolio: p,
+		Persona:   strings.TrimSpace(string(persona)),
+
+		EncryptedNotes: encrypted,
+	}, nil
+}
+
+// LoadAll loads every shelf directory under root, sorted by slug.
+// A missing root means no additional shelves. Shelves that fail to load are
+// returned as errors keyed by directory so the others can still be served.
+func LoadAll(root string, notes store.NotesPolicy) ([]*Shelf, map[string]error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, map[string]error{root: err}
+	}
+
+	var shelves []*Shelf
+	failed := make(map[string]error)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		s, err := Load(dir, notes)
+		if err != nil {
+			failed[dir] = err
+			continue
+		}
+		shelves = append(shelves, s)
+	}
+	sort.Slice(shelves, func(i, j int) bool { return shelves[i].Slug < shelves[j].Slug })
+	return shelves, failed
+}
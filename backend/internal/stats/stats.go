@@ -0,0 +1,198 @@
+// Package stats computes reading statistics for a shelf. The same numbers back
+// the get_reading_stats agent tool and GET /api/stats.
+package stats
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// TopAuthorsLimit is the number of authors in Stats.TopAuthors
+const TopAuthorsLimit = 5
+
+// Stats summarizes a shelf. Per-year, per-month and pace figures count finished books only.
+type Stats struct {
+	TotalBooks       int              `json:"total_books"`
+	FinishedBooks    int              `json:"finished_books"`
+	BooksPerYear     map[string]int   `json:"books_per_year"`     // "2024" -> books finished
+	BooksPerMonth    map[string]int   `json:"books_per_month"`    // "2024-03" -> books finished
+	BooksPerLanguage map[string]int   `json:"books_per_language"` // "ja" / "en" / "unknown" -> books
+	BooksByStatus    map[string]int   `json:"books_by_status"`
+	BooksPerGenre    map[string]int   `json:"books_per_genre"` // genre ID -> books
+	TopAuthors       []AuthorCount    `json:"top_authors"`
+	AbandonmentRate  float64          `json:"abandonment_rate"` // abandoned / (finished + abandoned)
+	Pace             Pace             `json:"pace"`
+	YearOverYear     []YearComparison `json:"year_over_year"`
+}
+
+// AuthorCount is an author and the number of books on the shelf by them
+type AuthorCount struct {
+	Author string `json:"author"`
+	Count  int    `json:"count"`
+}
+
+// Pace describes how regularly books are finished
+type Pace struct {
+	// AverageDaysBetween is the mean number of days between consecutive finishes (0 with fewer than two)
+	AverageDaysBetween float64 `json:"average_days_between"`
+	// LongestStreakMonths is the longest run of consecutive months with at least one finish
+	LongestStreakMonths int    `json:"longest_streak_months"`
+	StreakFrom          string `json:"streak_from,omitempty"` // first month of that run, "2024-03"
+	StreakTo            string `json:"streak_to,omitempty"`
+	// LongestGapDays is the longest time between two consecutive finishes
+	LongestGapDays int    `json:"longest_gap_days"`
+	GapFrom        string `json:"gap_from,omitempty"` // finish dates around the gap
+	GapTo          string `json:"gap_to,omitempty"`
+}
+
+// YearComparison compares a year's finished books with the year before
+type YearComparison struct {
+	Year          string   `json:"year"`
+	Books         int      `json:"books"`
+	PreviousYear  int      `json:"previous_year_books"`
+	Change        int      `json:"change"`
+	ChangePercent *float64 `json:"change_percent,omitempty"` // omitted when the previous year had no books
+}
+
+// Compute builds the statistics of books
+func Compute(books []model.Book) Stats {
+	s := Stats{
+		TotalBooks:       len(books),
+		BooksPerYear:     make(map[string]int),
+		BooksPerMonth:    make(map[string]int),
+		BooksPerLanguage: make(map[string]int),
+		BooksByStatus:    make(map[string]int),
+		BooksPerGenre:    make(map[string]int),
+	}
+
+	authors := make(map[string]*AuthorCount)
+	var finishDates []time.Time
+	for _, book := range books {
+		status := book.ReadingStatus()
+		s.BooksByStatus[status]++
+		s.BooksPerLanguage[cmp.Or(book.Language, "unknown")]++
+		for _, genre := range book.Genres {
+			s.BooksPerGenre[genre]++
+		}
+		for _, name := range book.Authors() {
+			key := model.AuthorKey(name)
+			if a, ok := authors[key]; ok {
+				a.Count++
+			} else {
+				authors[key] = &AuthorCount{Author: name, Count: 1}
+			}
+		}
+
+		if status != model.StatusFinished {
+			continue
+		}
+		s.FinishedBooks++
+		if len(book.FinishedAt) >= 4 {
+			s.BooksPerYear[book.FinishedAt[:4]]++
+		}
+		if len(book.FinishedAt) >= 7 {
+			s.BooksPerMonth[book.FinishedAt[:7]]++
+		}
+		if t, err := time.Parse(time.DateOnly, book.FinishedAt); err == nil {
+			finishDates = append(finishDates, t)
+		}
+	}
+
+	s.TopAuthors = topAuthors(authors)
+	if ended := s.BooksByStatus[model.StatusFinished] + s.BooksByStatus[model.StatusAbandoned]; ended > 0 {
+		s.AbandonmentRate = round2(float64(s.BooksByStatus[model.StatusAbandoned]) / float64(ended))
+	}
+	s.Pace = pace(finishDates, s.BooksPerMonth)
+	s.YearOverYear = yearOverYear(s.BooksPerYear)
+	return s
+}
+
+// topAuthors sorts by book count, then name, and keeps TopAuthorsLimit
+func topAuthors(authors map[string]*AuthorCount) []AuthorCount {
+	result := make([]AuthorCount, 0, len(authors))
+	for _, a := range authors {
+		result = append(result, *a)
+	}
+	slices.SortFunc(result, func(a, b AuthorCount) int {
+		return cmp.Or(b.Count-a.Count, cmp.Compare(a.Author, b.Author))
+	})
+	if len(result) > TopAuthorsLimit {
+		result = result[:TopAuthorsLimit]
+	}
+	return result
+}
+
+func pace(dates []time.Time, perMonth map[string]int) Pace {
+	var p Pace
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	if len(dates) >= 2 {
+		total := dates[len(dates)-1].Sub(dates[0]).Hours() / 24
+		p.AverageDaysBetween = round2(total / float64(len(dates)-1))
+		for i := 1; i < len(dates); i++ {
+			if gap := int(dates[i].Sub(dates[i-1]).Hours() / 24); gap > p.LongestGapDays {
+				p.LongestGapDays = gap
+				p.GapFrom = dates[i-1].Format(time.DateOnly)
+				p.GapTo = dates[i].Format(time.DateOnly)
+			}
+		}
+	}
+
+	months := make([]time.Time, 0, len(perMonth))
+	for month := range perMonth {
+		if t, err := time.Parse("2006-01", month); err == nil {
+			months = append(months, t)
+		}
+	}
+	slices.SortFunc(months, func(a, b time.Time) int { return a.Compare(b) })
+	run := 0
+	for i, m := range months {
+		if i > 0 && months[i-1].AddDate(0, 1, 0).Equal(m) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > p.LongestStreakMonths {
+			p.LongestStreakMonths = run
+			p.StreakFrom = months[i-run+1].Format("2006-01")
+			p.StreakTo = m.Format("2006-01")
+		}
+	}
+	return p
+}
+
+// yearOverYear lists every year from the first to the last with finishes,
+// including empty years in between, compared with the year before
+func yearOverYear(perYear map[string]int) []YearComparison {
+	var years []int
+	for y := range perYear {
+		if n, err := strconv.Atoi(y); err == nil {
+			years = append(years, n)
+		}
+	}
+	if len(years) == 0 {
+		return []YearComparison{}
+	}
+	first, last := slices.Min(years), slices.Max(years)
+
+	result := make([]YearComparison, 0, last-first+1)
+	for y := first; y <= last; y++ {
+		books := perYear[strconv.Itoa(y)]
+		prev := perYear[strconv.Itoa(y-1)]
+		c := YearComparison{Year: strconv.Itoa(y), Books: books, PreviousYear: prev, Change: books - prev}
+		if prev > 0 {
+			pct := round2(float64(books-prev) / float64(prev) * 100)
+			c.ChangePercent = &pct
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,137 @@
+// Package store provides persistent backends for the book data.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/notescrypt"
+)
+
+// ErrBookNotFound is returned when deleting a book that doesn't exist
+var ErrBookNotFound = errors.New("book not found")
+
+// LoadBooksJSON reads the book list from a books.json file
+func LoadBooksJSON(path string) ([]model.Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read books file: %w", err)
+	}
+
+	var books []model.Book
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, fmt.Errorf("failed to parse books JSON: %w", err)
+	}
+	MigrateBooks(books)
+
+	return books, nil
+}
+
+// SaveBooksJSON writes the book list to path atomically (temp file + rename)
+// in the same indented format as the hand-edited file
+func SaveBooksJSON(path string, books []model.Book) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(books); err != nil {
+		return fmt.Errorf("failed to encode books JSON: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".books-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	// CreateTemp uses 0600; keep the file readable like the hand-edited original
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write books file: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write books file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write books file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace books file: %w", err)
+	}
+	return nil
+}
+
+// JSONBookWriter persists admin edits back to books.json
+type JSONBookWriter struct {
+	path     string
+	notesKey *notescrypt.Key
+	mu       sync.Mutex
+}
+
+// NewJSONBookWriter creates a writer for the given books.json path.
+// With notesKey set, saved notes are encrypted whenever the file already holds encrypted notes.
+func NewJSONBookWriter(path string, notesKey *notescrypt.Key) *JSONBookWriter {
+	return &JSONBookWriter{path: path, notesKey: notesKey}
+}
+
+// Save replaces the book with the same ID, or appends it
+func (w *JSONBookWriter) Save(book model.Book) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	books, err := LoadBooksJSON(w.path)
+	if err != nil {
+		return err
+	}
+
+	// Keep an encrypted file fully encrypted
+	if encrypted, _ := NotesStats(books); encrypted > 0 && w.notesKey != nil {
+		if err := sealNotes(&book, w.notesKey); err != nil {
+			return err
+		}
+	}
+
+	replaced := false
+	for i := range books {
+		if books[i].ID == book.ID {
+			books[i] = book
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		books = append(books, book)
+	}
+
+	return SaveBooksJSON(w.path, books)
+}
+
+// Delete removes the book with the given ID
+func (w *JSONBookWriter) Delete(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	books, err := LoadBooksJSON(w.path)
+	if err != nil {
+		return err
+	}
+
+	kept := books[:0]
+	for _, book := range books {
+		if book.ID != id {
+			kept = append(kept, book)
+		}
+	}
+	if len(kept) == len(books) {
+		return ErrBookNotFound
+	}
+
+	return SaveBooksJSON(w.path, kept)
+}
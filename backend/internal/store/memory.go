@@ -0,0 +1,50 @@
+package store
+
+import (
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/related"
+	"talking-bookshelf/backend/internal/search"
+)
+
+// InMemoryBookRepository is a simple in-memory implementation of BookRepository
+type InMemoryBookRepository struct {
+	books   []model.Book
+	index   *search.Index
+	related *related.Index
+}
+
+// NewInMemoryBookRepository creates a new InMemoryBookRepository.
+// The books are an immutable snapshot, so the search and related-books indexes are built once here.
+func NewInMemoryBookRepository(books []model.Book) *InMemoryBookRepository {
+	return &InMemoryBookRepository{books: books, index: search.NewIndex(books), related: related.NewIndex(books)}
+}
+
+// GetByID finds a book by its ID
+func (r *InMemoryBookRepository) GetByID(id string) *model.Book {
+	for _, book := range r.books {
+		if book.ID == id {
+			return &book
+		}
+	}
+	return nil
+}
+
+// GetAll returns all books
+func (r *InMemoryBookRepository) GetAll() []model.Book {
+	return r.books
+}
+
+// Search returns the books most relevant to the query, best first
+func (r *InMemoryBookRepository) Search(query string) []search.Result {
+	return r.index.Search(query, search.DefaultLimit)
+}
+
+// SearchAll returns every book matching the query, best first
+func (r *InMemoryBookRepository) SearchAll(query string) []search.Result {
+	return r.index.Search(query, 0)
+}
+
+// Related returns the books most similar to the book with the given ID, best first
+func (r *InMemoryBookRepository) Related(id string) []related.Match {
+	return r.related.Related(id)
+}
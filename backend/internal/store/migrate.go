@@ -0,0 +1,24 @@
+package store
+
+import "talking-bookshelf/backend/internal/model"
+
+// MigrateBooks upgrades books saved by older versions in place and returns how many changed
+func MigrateBooks(books []model.Book) int {
+	n := 0
+	for i := range books {
+		if migrateBook(&books[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// migrateBook fills fields added after the book was saved.
+// Books from before reading statuses existed were all finished.
+func migrateBook(book *model.Book) bool {
+	if book.Status != "" {
+		return false
+	}
+	book.Status = model.StatusFinished
+	return true
+}
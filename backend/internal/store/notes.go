@@ -0,0 +1,130 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/notescrypt"
+)
+
+// ErrMixedNotes is returned when some private notes are encrypted and others are not
+var ErrMixedNotes = errors.New("book data mixes encrypted and plaintext private notes")
+
+// NotesPolicy says how private notes are read from a data source
+type NotesPolicy struct {
+	// Key decrypts sealed notes; nil when NOTES_KEY / NOTES_KEY_FILE is unset
+	Key *notescrypt.Key
+	// AllowMixed accepts data where only some notes are encrypted
+	AllowMixed bool
+}
+
+// NotesPolicyFromEnv returns how private notes are decrypted, from NOTES_KEY /
+// NOTES_KEY_FILE and ALLOW_MIXED_NOTES
+func NotesPolicyFromEnv() (NotesPolicy, error) {
+	key, err := notescrypt.LoadKeyFromEnv()
+	if err != nil {
+		return NotesPolicy{}, err
+	}
+	return NotesPolicy{Key: key, AllowMixed: os.Getenv("ALLOW_MIXED_NOTES") == "true"}, nil
+}
+
+// NotesStats counts the non-empty private notes that are encrypted and plaintext
+func NotesStats(books []model.Book) (encrypted, plaintext int) {
+	for _, book := range books {
+		switch {
+		case notescrypt.IsEncrypted(book.PrivateNotes):
+			encrypted++
+		case strings.TrimSpace(book.PrivateNotes) != "":
+			plaintext++
+		}
+	}
+	return encrypted, plaintext
+}
+
+// Open checks books read from source against the policy and decrypts their
+// notes in place. It reports whether the source is encrypted, i.e. whether
+// notes written back to it must be encrypted too.
+func (p NotesPolicy) Open(books []model.Book, source string) (bool, error) {
+	encrypted, plaintext := NotesStats(books)
+	if encrypted > 0 && plaintext > 0 && !p.AllowMixed {
+		return false, fmt.Errorf("%s: %w (%d encrypted, %d plaintext; run `bookshelf encrypt` or set ALLOW_MIXED_NOTES=true)",
+			source, ErrMixedNotes, encrypted, plaintext)
+	}
+	if encrypted == 0 {
+		if p.Key != nil && plaintext > 0 {
+			log.Printf("[CRYPT] %s has %d plaintext notes; run `go run ./cmd/bookshelf encrypt` to encrypt them", source, plaintext)
+		}
+		return false, nil
+	}
+	if p.Key == nil {
+		return false, fmt.Errorf("%s has encrypted notes but NOTES_KEY / NOTES_KEY_FILE is not set", source)
+	}
+	if err := DecryptNotes(books, p.Key); err != nil {
+		return false, fmt.Errorf("%s: %w", source, err)
+	}
+	return true, nil
+}
+
+// DecryptNotes replaces sealed notes with their plaintext; plaintext notes are kept
+func DecryptNotes(books []model.Book, key *notescrypt.Key) error {
+	for i := range books {
+		if !notescrypt.IsEncrypted(books[i].PrivateNotes) {
+			continue
+		}
+		notes, err := key.Decrypt(books[i].PrivateNotes, books[i].ID)
+		if err != nil {
+			return fmt.Errorf("book %s: %w", books[i].ID, err)
+		}
+		books[i].PrivateNotes = notes
+	}
+	return nil
+}
+
+// EncryptNotes seals every non-empty plaintext note and returns how many were sealed
+func EncryptNotes(books []model.Book, key *notescrypt.Key) (int, error) {
+	n := 0
+	for i := range books {
+		before := books[i].PrivateNotes
+		if err := sealNotes(&books[i], key); err != nil {
+			return n, err
+		}
+		if books[i].PrivateNotes != before {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RewrapNotes moves every sealed note from key to next and returns how many were rewrapped
+func RewrapNotes(books []model.Book, key, next *notescrypt.Key) (int, error) {
+	n := 0
+	for i := range books {
+		if !notescrypt.IsEncrypted(books[i].PrivateNotes) {
+			continue
+		}
+		notes, err := key.Rewrap(books[i].PrivateNotes, next)
+		if err != nil {
+			return n, fmt.Errorf("book %s: %w", books[i].ID, err)
+		}
+		books[i].PrivateNotes = notes
+		n++
+	}
+	return n, nil
+}
+
+// sealNotes encrypts the book's notes unless they are empty or already sealed
+func sealNotes(book *model.Book, key *notescrypt.Key) error {
+	if strings.TrimSpace(book.PrivateNotes) == "" || notescrypt.IsEncrypted(book.PrivateNotes) {
+		return nil
+	}
+	notes, err := key.Encrypt(book.PrivateNotes, book.ID)
+	if err != nil {
+		return fmt.Errorf("book %s: %w", book.ID, err)
+	}
+	book.PrivateNotes = notes
+	return nil
+}
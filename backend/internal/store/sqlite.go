@@ -0,0 +1,304 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"talking-bookshelf/backend/internal/model"
+	"talking-bookshelf/backend/internal/notescrypt"
+	"talking-bookshelf/backend/internal/related"
+	"talking-bookshelf/backend/internal/search"
+
+	_ "modernc.org/sqlite" // Pure-Go driver (the Docker build uses CGO_ENABLED=0)
+)
+
+// schema creates the books table.
+// The full model.Book is stored as JSON in the data column so that new fields
+// don't need a migration; the other columns exist for ordering and lookups.
+const schema = `
+CREATE TABLE IF NOT EXISTS books (
+	id          TEXT PRIMARY KEY,
+	position    INTEGER NOT NULL,
+	title       TEXT NOT NULL,
+	author      TEXT NOT NULL,
+	finished_at TEXT NOT NULL DEFAULT '',
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_books_position ON books(position);
+`
+
+// SQLiteBookRepository is a BookRepository backed by an embedded SQLite file
+type SQLiteBookRepository struct {
+	db *sql.DB
+
+	// notesKey is set by OpenNotes when the database holds encrypted notes;
+	// reads then decrypt them and writes encrypt them
+	notesKey *notescrypt.Key
+
+	indexMu sync.Mutex
+	index   *search.Index
+	related *related.Index
+}
+
+// NewSQLiteBookRepository opens (or creates) the database at path and ensures the schema exists
+func NewSQLiteBookRepository(path string) (*SQLiteBookRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows a single writer; serialize access through one connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return &SQLiteBookRepository{db: db}, nil
+}
+
+// Close closes the underlying database
+func (r *SQLiteBookRepository) Close() error {
+	return r.db.Close()
+}
+
+// OpenNotes checks the stored notes against the policy and, if they are
+// encrypted, makes the repository decrypt on read and encrypt on write.
+// Call it once after opening, before the repository is shared.
+func (r *SQLiteBookRepository) OpenNotes(policy NotesPolicy, source string) error {
+	books := r.GetAll()
+	encrypted, err := policy.Open(books, source)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		r.notesKey = policy.Key
+		r.invalidateIndex()
+	}
+	return nil
+}
+
+// EncryptedNotes reports whether the database holds encrypted notes
+func (r *SQLiteBookRepository) EncryptedNotes() bool {
+	return r.notesKey != nil
+}
+
+// Count returns the number of stored books
+func (r *SQLiteBookRepository) Count() (int, error) {
+	var n int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM books`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count books: %w", err)
+	}
+	return n, nil
+}
+
+// ImportBooks upserts books in a single transaction, keeping their order
+func (r *SQLiteBookRepository) ImportBooks(books []model.Book) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO books (id, position, title, author, finished_at, data)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			position = excluded.position,
+			title = excluded.title,
+			author = excluded.author,
+			finished_at = excluded.finished_at,
+			data = excluded.data`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, book := range books {
+		data, err := r.encodeBook(book)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(book.ID, i, book.Title, book.Author, book.FinishedAt, string(data)); err != nil {
+			return fmt.Errorf("failed to insert book %s: %w", book.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import: %w", err)
+	}
+	r.invalidateIndex()
+	return nil
+}
+
+// Save inserts the book, or updates it in place if the ID already exists.
+// New books are appended after the current last position.
+func (r *SQLiteBookRepository) Save(book model.Book) error {
+	data, err := r.encodeBook(book)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO books (id, position, title, author, finished_at, data)
+		VALUES (?, (SELECT COALESCE(MAX(position), -1) + 1 FROM books), ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title,
+			author = excluded.author,
+			finished_at = excluded.finished_at,
+			data = excluded.data`,
+		book.ID, book.Title, book.Author, book.FinishedAt, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save book %s: %w", book.ID, err)
+	}
+	r.invalidateIndex()
+	return nil
+}
+
+// Delete removes the book with the given ID
+func (r *SQLiteBookRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM books WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete book %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrBookNotFound
+	}
+	r.invalidateIndex()
+	return nil
+}
+
+// ImportJSON imports every book from a books.json file
+func (r *SQLiteBookRepository) ImportJSON(path string) (int, error) {
+	books, err := LoadBooksJSON(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.ImportBooks(books); err != nil {
+		return 0, err
+	}
+	return len(books), nil
+}
+
+// GetByID finds a book by its ID
+func (r *SQLiteBookRepository) GetByID(id string) *model.Book {
+	var data string
+	err := r.db.QueryRow(`SELECT data FROM books WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		log.Printf("[STORE] Failed to get book %s: %v", id, err)
+		return nil
+	}
+
+	book, err := r.decodeBook(data)
+	if err != nil {
+		log.Printf("[STORE] Failed to decode book %s: %v", id, err)
+		return nil
+	}
+	return &book
+}
+
+// GetAll returns all books in import order
+func (r *SQLiteBookRepository) GetAll() []model.Book {
+	rows, err := r.db.Query(`SELECT data FROM books ORDER BY position, id`)
+	if err != nil {
+		log.Printf("[STORE] Failed to list books: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var books []model.Book
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			log.Printf("[STORE] Failed to scan book: %v", err)
+			return nil
+		}
+		book, err := r.decodeBook(data)
+		if err != nil {
+			log.Printf("[STORE] Failed to decode book: %v", err)
+			continue
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[STORE] Failed to list books: %v", err)
+	}
+	return books
+}
+
+// encodeBook serializes the data column, encrypting the notes if the database is encrypted
+func (r *SQLiteBookRepository) encodeBook(book model.Book) ([]byte, error) {
+	if r.notesKey != nil {
+		if err := sealNotes(&book, r.notesKey); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(book)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode book %s: %w", book.ID, err)
+	}
+	return data, nil
+}
+
+// decodeBook parses the data column, decrypting the notes if the database is encrypted
+func (r *SQLiteBookRepository) decodeBook(data string) (model.Book, error) {
+	var book model.Book
+	if err := json.Unmarshal([]byte(data), &book); err != nil {
+		return book, err
+	}
+	migrateBook(&book)
+	if r.notesKey != nil && notescrypt.IsEncrypted(book.PrivateNotes) {
+		notes, err := r.notesKey.Decrypt(book.PrivateNotes, book.ID)
+		if err != nil {
+			return book, err
+		}
+		book.PrivateNotes = notes
+	}
+	return book, nil
+}
+
+// Search returns the books most relevant to the query, best first.
+// Ranking uses the same in-process index as InMemoryBookRepository; it is
+// rebuilt lazily after writes rather than kept in SQLite FTS.
+func (r *SQLiteBookRepository) Search(query string) []search.Result {
+	return r.searchIndex().Search(query, search.DefaultLimit)
+}
+
+// SearchAll returns every book matching the query, best first
+func (r *SQLiteBookRepository) SearchAll(query string) []search.Result {
+	return r.searchIndex().Search(query, 0)
+}
+
+// searchIndex returns the cached index, rebuilding it if a write invalidated it
+func (r *SQLiteBookRepository) searchIndex() *search.Index {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+	if r.index == nil {
+		r.index = search.NewIndex(r.GetAll())
+	}
+	return r.index
+}
+
+// Related returns the books most similar to the book with the given ID, best first.
+// Like the search index, the related-books index is rebuilt lazily after writes.
+func (r *SQLiteBookRepository) Related(id string) []related.Match {
+	r.indexMu.Lock()
+	defer r.indexMu.Unlock()
+	if r.related == nil {
+		r.related = related.NewIndex(r.GetAll())
+	}
+	return r.related.Related(id)
+}
+
+func (r *SQLiteBookRepository) invalidateIndex() {
+	r.indexMu.Lock()
+	r.index = nil
+	r.related = nil
+	r.indexMu.Unlock()
+}
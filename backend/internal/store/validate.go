@@ -0,0 +1,31 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"talking-bookshelf/backend/internal/model"
+)
+
+// ValidateBooks rejects book data that would break the handlers or the agent.
+// Used before swapping in reloaded data so a bad edit never replaces a good shelf.
+func ValidateBooks(books []model.Book) error {
+	if len(books) == 0 {
+		return errors.New("no books found")
+	}
+
+	seen := make(map[string]bool, len(books))
+	for i, book := range books {
+		if book.ID == "" {
+			return fmt.Errorf("book #%d has no id", i+1)
+		}
+		if book.Title == "" {
+			return fmt.Errorf("book %s has no title", book.ID)
+		}
+		if seen[book.ID] {
+			return fmt.Errorf("duplicate book id %s", book.ID)
+		}
+		seen[book.ID] = true
+	}
+	return nil
+}
@@ -1,25 +1,48 @@
 import { useState, useRef, useEffect } from 'react';
 import './App.css';
 
+interface Quote {
+  bookId: string;
+  title: string;
+  text: string;
+  location?: string;
+  page?: string;
+}
+
 interface Message {
   role: 'user' | 'assistant';
   content: string;
   emotion?: string;
   suggestions?: string[];
+  quotes?: Record<string, Quote>;
 }
 
 interface ChatResponse {
   response: string;
   emotion: string;
   suggestions: string[];
+  quotes?: Record<string, Quote>;
   sessionId: string;
 }
 
-function formatResponse(text: string): string {
-  return text.replace(
-    /\[book::(.+?)::(.+?)\]/g,
-    '<span class="book-link">$1</span>'
-  );
+function escapeHtml(text: string): string {
+  return text
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;');
+}
+
+function formatResponse(text: string, quotes: Record<string, Quote> = {}): string {
+  return text
+    .replace(/\[book::(.+?)::(.+?)\]/g, '<span class="book-link">$1</span>')
+    .replace(/\[quote::[^:\]]+::\d+\]/g, (ref) => {
+      const quote = quotes[ref];
+      if (!quote) return '';
+      const where = quote.page ? `p.${quote.page}` : quote.location ? `loc. ${quote.location}` : '';
+      const label = [quote.title, where].filter(Boolean).join(', ');
+      return `<cite class="quote-ref" title="${escapeHtml(quote.text)}">(${escapeHtml(label)})</cite>`;
+    });
 }
 
 function App() {
@@ -60,6 +83,7 @@ function App() {
         content: data.response,
         emotion: data.emotion,
         suggestions: data.suggestions,
+        quotes: data.quotes,
       };
       setMessages((prev) => [...prev, assistantMessage]);
     } catch {
@@ -95,7 +119,7 @@ function App() {
               <div
                 className="bubble"
                 dangerouslySetInnerHTML={{
-                  __html: msg.role === 'assistant' ? formatResponse(msg.content) : msg.content,
+                  __html: msg.role === 'assistant' ? formatResponse(msg.content, msg.quotes) : msg.content,
                 }}
               />
             </div>